@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sepulchrestudios/go-service/src/config"
+	"github.com/sepulchrestudios/go-service/src/database"
+	"github.com/sepulchrestudios/go-service/src/database/migrations"
+)
+
+// usage describes how to invoke the migration command.
+const usage = `Usage: migrate [configuration flags] <command> [command flags]
+
+Commands:
+  up       apply every pending migration (-to limits the highest version, -dry-run only lists them)
+  down     roll back the most recent migrations (-steps sets how many, -dry-run only lists them)
+  status   list every migration and whether it has been applied
+
+Configuration flags set service properties (e.g., --database-host=localhost) on top of the usual configuration files
+and environment variables.
+`
+
+// errUsage is returned when the command-line arguments cannot be understood.
+var errUsage = errors.New("invalid arguments")
+
+// Connect to the intended database using the provided environment configuration. Returns the database connection plus
+// any error that may have occurred.
+func connectToDatabaseFromConfig(ctx context.Context, envConfig config.Contract) (database.Contract, error) {
+	// Resolve the DB password from its configured secret sources
+	secrets, err := config.NewSecretPropertyResolverFromConfig(envConfig)
+	if err != nil {
+		return nil, err
+	}
+	dbPassword, exists, err := secrets.ResolveSecretProperty(ctx, config.PropertyNameDatabasePassword)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("Cannot read property from configuration (make sure it exists): %s",
+			config.PropertyNameDatabasePassword)
+	}
+
+	// Create the Postgres connection from the environment configuration
+	connectionArguments := &database.PostgresDatabaseConnectionArguments{}
+	if err = config.Bind(envConfig, connectionArguments); err != nil {
+		return nil, err
+	}
+	connectionArguments.Password = dbPassword
+	return database.NewPostgresDatabaseConnection(connectionArguments, false)
+}
+
+// printMigrations writes the version and name of each migration after the verb, or a note if there are none.
+func printMigrations(w io.Writer, verb string, migrationList []database.Migration) {
+	if len(migrationList) == 0 {
+		fmt.Fprintln(w, "Nothing to migrate.")
+		return
+	}
+	for _, migration := range migrationList {
+		fmt.Fprintf(w, "%s %d_%s\n", verb, migration.Version, migration.Name)
+	}
+}
+
+// printStatus writes a table of every migration and its state.
+func printStatus(w io.Writer, statuses []database.MigrationStatus) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "-"
+		if !status.AppliedAt.IsZero() {
+			appliedAt = status.AppliedAt.UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintf(table, "%d\t%s\t%s\t%s\n", status.Version, status.Name, status.State, appliedAt)
+	}
+	return table.Flush()
+}
+
+// run parses the command and its flags, then runs it against the configured database. Returns any error that may
+// have occurred.
+func run(ctx context.Context, args []string, stdout io.Writer) error {
+	// Configuration flags come first, so whatever remains after them is the command and its own flags
+	flagConfig, err := config.ParseFlagConfiguration(args)
+	if err != nil {
+		return err
+	}
+	commandArgs := flagConfig.GetArguments()
+	if len(commandArgs) == 0 || (commandArgs[0] != "up" && commandArgs[0] != "down" && commandArgs[0] != "status") {
+		return errUsage
+	}
+	commandFlags := flag.NewFlagSet(commandArgs[0], flag.ContinueOnError)
+	commandFlags.SetOutput(io.Discard)
+	isDryRun := commandFlags.Bool("dry-run", false, "list the migrations without changing the database")
+	steps := commandFlags.Int("steps", 1, "number of migrations to roll back")
+	targetVersion := commandFlags.Int64("to", 0, "highest version to apply (zero applies every pending migration)")
+	if err = commandFlags.Parse(commandArgs[1:]); err != nil {
+		return fmt.Errorf("%w: %w", errUsage, err)
+	}
+
+	envConfig, err := config.LoadCompositeConfigurationWithArguments(
+		args[:len(args)-len(commandArgs)], config.DefaultConfigurationFilePath,
+	)
+	if err != nil {
+		return fmt.Errorf("Cannot load environment configuration: %w", err)
+	}
+	connection, err := connectToDatabaseFromConfig(ctx, envConfig)
+	if err != nil {
+		return fmt.Errorf("Cannot connect to database: %w", err)
+	}
+	migrator, err := database.NewMigratorFromFS(connection, migrations.FS, ".", &database.MigratorOptions{
+		DryRun: *isDryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	appliedVerb, rolledBackVerb := "Applied", "Rolled back"
+	if *isDryRun {
+		appliedVerb, rolledBackVerb = "Would apply", "Would roll back"
+	}
+	switch commandArgs[0] {
+	case "up":
+		var applied []database.Migration
+		if *targetVersion > 0 {
+			applied, err = migrator.UpTo(ctx, *targetVersion)
+		} else {
+			applied, err = migrator.Up(ctx)
+		}
+		printMigrations(stdout, appliedVerb, applied)
+		return err
+	case "down":
+		rolledBack, err := migrator.Down(ctx, *steps)
+		printMigrations(stdout, rolledBackVerb, rolledBack)
+		return err
+	default:
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		return printStatus(stdout, statuses)
+	}
+}
+
+func main() {
+	err := run(context.Background(), os.Args[1:], os.Stdout)
+	if errors.Is(err, errUsage) {
+		fmt.Fprintf(os.Stderr, "%v\n\n%s", err, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalln("Cannot migrate database: ", err)
+	}
+}
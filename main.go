@@ -7,24 +7,28 @@ import (
 	"net"
 	gohttp "net/http"
 	"os"
-	"strconv"
 	"time"
 
 	devcycle "github.com/devcyclehq/go-server-sdk/v2"
 	devcycleapi "github.com/devcyclehq/go-server-sdk/v2/api"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sepulchrestudios/go-service/src/cache"
 	"github.com/sepulchrestudios/go-service/src/config"
 	"github.com/sepulchrestudios/go-service/src/database"
+	"github.com/sepulchrestudios/go-service/src/database/migrations"
 	"github.com/sepulchrestudios/go-service/src/event"
 	"github.com/sepulchrestudios/go-service/src/feature"
 	servicelogger "github.com/sepulchrestudios/go-service/src/log"
 	"github.com/sepulchrestudios/go-service/src/mail"
+	"github.com/sepulchrestudios/go-service/src/metrics"
+	"github.com/sepulchrestudios/go-service/src/ratelimit"
 	"github.com/sepulchrestudios/go-service/src/server"
 	"github.com/sepulchrestudios/go-service/src/service"
 	"github.com/sepulchrestudios/go-service/src/work"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -32,62 +36,156 @@ import (
 // Connect to the intended cache using the provided environment configuration. Returns the cache implementation plus
 // any error that may have occurred.
 func connectToCacheFromConfig(
-	ctx context.Context, envConfig config.Contract, isDebugModeActive bool, debugLogger servicelogger.DebugContract,
+	ctx context.Context, envConfig config.Contract, secrets *config.SecretPropertyResolver,
+	metricsRegistry prometheus.Registerer, isDebugModeActive bool, debugLogger servicelogger.DebugContract,
 ) (cache.Contract, error) {
-	// Resolve the cache password from either a file path or the direct property
-	//
-	// Cache password is optional so being completely blank regardless of existence is a valid scenario
-	cachePassword, _, err := readSecretFromFileOrEnvFallback(
-		config.PropertyNameCachePasswordFile, config.PropertyNameCachePassword, envConfig,
-	)
+	// Create the cache implementation selected by the configured driver
+	driver, _ := envConfig.GetProperty(config.PropertyNameCacheDriver)
+	var cacheImplementation cache.Contract
+	var err error
+	switch cache.Driver(driver) {
+	case cache.DriverMemory:
+		connectionArguments := &cache.MemoryConnectionArguments{}
+		if err = config.Bind(envConfig, connectionArguments); err != nil {
+			return nil, err
+		}
+		cacheImplementation, err = cache.NewMemory(connectionArguments)
+	case cache.DriverRedis, "":
+		cacheImplementation, err = connectToRedisFromConfig(ctx, envConfig, secrets)
+	case cache.DriverTiered:
+		cacheImplementation, err = connectToTieredCacheFromConfig(ctx, envConfig, secrets)
+	default:
+		err = fmt.Errorf("Unsupported cache driver: %s", driver)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	// Create the cache connection from the environment configuration
-	var cacheAddr string
-	cacheHost, exists := envConfig.GetProperty(config.PropertyNameCacheHost)
-	if !exists {
-		return nil, fmt.Errorf("Cannot read property from configuration: %s", config.PropertyNameCacheHost)
-	}
-	cachePort, _ := envConfig.GetProperty(config.PropertyNameCachePort)
-	if cachePort != "" {
-		cacheAddr = fmt.Sprintf("%s:%s", cacheHost, cachePort)
-	} else {
-		cacheAddr = cacheHost
+	// Prefix every key with the namespace (if any) so that services sharing the cache cannot collide
+	namespace, _ := envConfig.GetProperty(config.PropertyNameCacheNamespace)
+	if namespace != "" {
+		namespaceVersion, _ := envConfig.GetProperty(config.PropertyNameCacheNamespaceVersion)
+		namespacedCache, err := cache.NewNamespaced(cacheImplementation, namespace, namespaceVersion)
+		if err != nil {
+			_ = cacheImplementation.Close()
+			return nil, err
+		}
+		cacheImplementation = namespacedCache
 	}
-	cacheIdentifier, exists := envConfig.GetProperty(config.PropertyNameCacheIdentifier)
-	if !exists {
-		return nil, fmt.Errorf("Cannot read property from configuration: %s", config.PropertyNameCacheIdentifier)
+
+	// Encrypt every cached value when encryption keys have been configured
+	//
+	// The keys are optional so being completely blank regardless of existence is a valid scenario
+	encryptionKeys, _, err := secrets.ResolveSecretProperty(ctx, config.PropertyNameCacheEncryptionKeys)
+	if err != nil {
+		_ = cacheImplementation.Close()
+		return nil, err
 	}
-	cacheUsername, _ := envConfig.GetProperty(config.PropertyNameCacheUsername)
-	connectionArguments := &cache.RedisConnectionArguments{
-		CacheConnectionArguments: cache.CacheConnectionArguments{
-			CacheIdentifier: cacheIdentifier,
-		},
-		Addr:     cacheAddr,
-		Password: cachePassword,
-		Username: cacheUsername,
+	if encryptionKeys != "" {
+		encryptedCache, err := connectToEncryptedCacheFromConfig(envConfig, cacheImplementation, encryptionKeys)
+		if err != nil {
+			_ = cacheImplementation.Close()
+			return nil, err
+		}
+		cacheImplementation = encryptedCache
 	}
-	cacheImplementation, err := cache.NewRedis(ctx, connectionArguments)
+
+	// Record metrics for every cache operation so they can be scraped
+	instrumentedCache, err := cache.NewInstrumented(cacheImplementation, metricsRegistry, driver)
 	if err != nil {
+		_ = cacheImplementation.Close()
 		return nil, err
 	}
+	cacheImplementation = instrumentedCache
 	if isDebugModeActive {
 		return cache.NewDebug(cacheImplementation, debugLogger), nil
 	}
 	return cacheImplementation, nil
 }
 
+// Wrap the cache so that every value is encrypted with the provided keys using the environment configuration. Returns
+// the encrypted cache plus any error that may have occurred.
+func connectToEncryptedCacheFromConfig(
+	envConfig config.Contract, cacheImplementation cache.Contract, encryptionKeys string,
+) (*cache.Encrypted, error) {
+	options := &cache.EncryptedOptions{}
+	if err := config.Bind(envConfig, options); err != nil {
+		return nil, err
+	}
+	keys, err := cache.ParseEncryptionKeys(encryptionKeys)
+	if err != nil {
+		return nil, err
+	}
+	return cache.NewEncrypted(cacheImplementation, keys, options)
+}
+
+// Connect to the Redis cache and put a local in-memory tier in front of it using the provided environment
+// configuration. Returns the tiered cache plus any error that may have occurred.
+func connectToTieredCacheFromConfig(
+	ctx context.Context, envConfig config.Contract, secrets *config.SecretPropertyResolver,
+) (*cache.Tiered, error) {
+	connectionArguments := &cache.TieredConnectionArguments{}
+	if err := config.Bind(envConfig, connectionArguments); err != nil {
+		return nil, err
+	}
+	redisCache, err := connectToRedisFromConfig(ctx, envConfig, secrets)
+	if err != nil {
+		return nil, err
+	}
+	tieredCache, err := cache.NewTiered(ctx, redisCache, connectionArguments)
+	if err != nil {
+		_ = redisCache.Close()
+		return nil, err
+	}
+	return tieredCache, nil
+}
+
+// Connect to the Redis cache using the provided environment configuration. Returns the Redis cache plus any error that
+// may have occurred.
+func connectToRedisFromConfig(
+	ctx context.Context, envConfig config.Contract, secrets *config.SecretPropertyResolver,
+) (*cache.Redis, error) {
+	// Resolve the cache passwords from their configured secret sources
+	//
+	// Cache passwords are optional so being completely blank regardless of existence is a valid scenario
+	cachePassword, _, err := secrets.ResolveSecretProperty(ctx, config.PropertyNameCachePassword)
+	if err != nil {
+		return nil, err
+	}
+	sentinelPassword, _, err := secrets.ResolveSecretProperty(ctx, config.PropertyNameCacheSentinelPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the cache connection from the environment configuration
+	//
+	// The host is only optional when the sentinel or cluster node addresses are listed instead
+	cacheSettings := struct {
+		cache.RedisConnectionArguments
+
+		Host string `config:"CACHE_HOST"`
+		Port string `config:"CACHE_PORT"`
+	}{}
+	if err = config.Bind(envConfig, &cacheSettings); err != nil {
+		return nil, err
+	}
+	connectionArguments := &cacheSettings.RedisConnectionArguments
+	connectionArguments.Addr = cacheSettings.Host
+	if cacheSettings.Host != "" && cacheSettings.Port != "" {
+		connectionArguments.Addr = net.JoinHostPort(cacheSettings.Host, cacheSettings.Port)
+	}
+	connectionArguments.Password = cachePassword
+	connectionArguments.SentinelPassword = sentinelPassword
+	return cache.NewRedis(ctx, connectionArguments)
+}
+
 // Connect to the intended database using the provided environment configuration. Returns the database connection plus
 // any error that may have occurred.
 func connectToDatabaseFromConfig(
-	envConfig config.Contract, isDebugModeActive bool,
+	ctx context.Context, envConfig config.Contract, secrets *config.SecretPropertyResolver, isDebugModeActive bool,
 ) (database.Contract, error) {
-	// Resolve the DB password from either a file path or the direct property
-	dbPassword, exists, err := readSecretFromFileOrEnvFallback(
-		config.PropertyNameDatabasePasswordFile, config.PropertyNameDatabasePassword, envConfig,
-	)
+	// Resolve the DB password from its configured secret sources
+	dbPassword, exists, err := secrets.ResolveSecretProperty(ctx, config.PropertyNameDatabasePassword)
 	if err != nil {
 		return nil, err
 	}
@@ -97,44 +195,21 @@ func connectToDatabaseFromConfig(
 	}
 
 	// Create the Postgres connection from the environment configuration
-	dbHost, exists := envConfig.GetProperty(config.PropertyNameDatabaseHost)
-	if !exists {
-		return nil, fmt.Errorf("Cannot read property from configuration: %s", config.PropertyNameDatabaseHost)
-	}
-	dbUsername, exists := envConfig.GetProperty(config.PropertyNameDatabaseUsername)
-	if !exists {
-		return nil, fmt.Errorf("Cannot read property from configuration: %s", config.PropertyNameDatabaseUsername)
-	}
-	dbName, exists := envConfig.GetProperty(config.PropertyNameDatabaseName)
-	if !exists {
-		return nil, fmt.Errorf("Cannot read property from configuration: %s", config.PropertyNameDatabaseName)
-	}
-	dbPort, _ := envConfig.GetProperty(config.PropertyNameDatabasePort)
-	dbSSLMode, _ := envConfig.GetProperty(config.PropertyNameDatabaseSSLMode)
-	dbTimezone, _ := envConfig.GetProperty(config.PropertyNameDatabaseTimezone)
-	connectionArguments := &database.PostgresDatabaseConnectionArguments{
-		DatabaseConnectionArguments: database.DatabaseConnectionArguments{
-			DatabaseName: dbName,
-			Host:         dbHost,
-			Password:     dbPassword,
-			Port:         dbPort,
-			Username:     dbUsername,
-		},
-		SSLMode:  dbSSLMode,
-		Timezone: dbTimezone,
+	connectionArguments := &database.PostgresDatabaseConnectionArguments{}
+	if err = config.Bind(envConfig, connectionArguments); err != nil {
+		return nil, err
 	}
+	connectionArguments.Password = dbPassword
 	return database.NewPostgresDatabaseConnection(connectionArguments, isDebugModeActive)
 }
 
 // Connect to the intended feature flag service using the provided environment configuration. Returns the OpenFeature
 // provider, a channel that will be unblocked upon readiness, plus any error that may have occurred.
 func connectToFeatureFlagServiceFromConfig(
-	envConfig config.Contract,
+	ctx context.Context, envConfig config.Contract, secrets *config.SecretPropertyResolver,
 ) (openfeature.FeatureProvider, chan devcycleapi.ClientEvent, error) {
-	// Resolve the SDK key from either a file path or the direct property
-	sdkKey, exists, err := readSecretFromFileOrEnvFallback(
-		config.PropertyNameFeatureFlagSDKKeyFile, config.PropertyNameFeatureFlagSDKKey, envConfig,
-	)
+	// Resolve the SDK key from its configured secret sources
+	sdkKey, exists, err := secrets.ResolveSecretProperty(ctx, config.PropertyNameFeatureFlagSDKKey)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -142,6 +217,13 @@ func connectToFeatureFlagServiceFromConfig(
 		return nil, nil, fmt.Errorf("Cannot read property from configuration (make sure it exists): %s",
 			config.PropertyNameFeatureFlagSDKKey)
 	}
+	pollingInterval, err := config.GetPropertyAsDurationWithDefault(
+		envConfig, config.PropertyNameFeatureFlagPollingInterval, 1*time.Minute,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Create the DevCycle client and OpenFeature provider with basic options
 	onInitializedChannel := make(chan devcycleapi.ClientEvent)
 	options := devcycle.Options{
@@ -149,7 +231,7 @@ func connectToFeatureFlagServiceFromConfig(
 		EnableEdgeDB:                 false,
 		EnableCloudBucketing:         false,
 		EventFlushIntervalMS:         30 * time.Second,
-		ConfigPollingIntervalMS:      1 * time.Minute,
+		ConfigPollingIntervalMS:      pollingInterval,
 		RequestTimeout:               30 * time.Second,
 		DisableAutomaticEventLogging: false,
 		DisableCustomEventLogging:    false,
@@ -161,6 +243,164 @@ func connectToFeatureFlagServiceFromConfig(
 	return provider, onInitializedChannel, nil
 }
 
+// applyLogLevelFromConfig sets the level of the logger from the log level property if it has been set. Returns any
+// error that may have occurred.
+func applyLogLevelFromConfig(envConfig config.Contract, logger *servicelogger.StandardLogger) error {
+	levelName, exists := envConfig.GetProperty(config.PropertyNameLogLevel)
+	if !exists || levelName == "" {
+		return nil
+	}
+	level, err := zapcore.ParseLevel(levelName)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", config.ErrInvalidPropertyValue, config.PropertyNameLogLevel, err)
+	}
+	return logger.SetLevel(level)
+}
+
+// Create the rate limiter for incoming gRPC calls using the provided environment configuration and cache. The rate
+// limiter shares its quotas with every other instance if the cache is backed by Redis. Returns the rate limiter plus
+// any error that may have occurred.
+func createRateLimiterFromConfig(
+	envConfig config.Contract, cacheImplementation cache.Contract, logger *servicelogger.StandardLogger,
+) (*ratelimit.Limiter, error) {
+	rule, err := getRateLimitRuleFromConfig(envConfig)
+	if err != nil {
+		return nil, err
+	}
+	return ratelimit.NewLimiterFromCache(cacheImplementation, rule, &ratelimit.LimiterOptions{
+		OnError: func(err error) {
+			logger.Warn("Rate limiter cannot reach the cache; using in-memory quotas", zap.Error(err))
+		},
+	})
+}
+
+// Read the rate limiting rule for incoming gRPC calls from the provided environment configuration. Returns the rule
+// plus any error that may have occurred.
+func getRateLimitRuleFromConfig(envConfig config.Contract) (ratelimit.Rule, error) {
+	settings := struct {
+		Algorithm string        `config:"RATE_LIMIT_ALGORITHM"`
+		Burst     int           `config:"RATE_LIMIT_BURST"`
+		Requests  int           `config:"RATE_LIMIT_REQUESTS"`
+		Window    time.Duration `config:"RATE_LIMIT_WINDOW"`
+	}{}
+	if err := config.Bind(envConfig, &settings); err != nil {
+		return ratelimit.Rule{}, err
+	}
+	return ratelimit.Rule{
+		Algorithm: ratelimit.Algorithm(settings.Algorithm),
+		Burst:     settings.Burst,
+		Limit:     settings.Requests,
+		Window:    settings.Window,
+	}, nil
+}
+
+// migrateDatabaseFromConfig applies every pending migration embedded within the binary if migrating at startup has been
+// turned on within the provided environment configuration. Other instances starting at the same time wait for the
+// migrations to finish. Returns any error that may have occurred.
+func migrateDatabaseFromConfig(
+	ctx context.Context, envConfig config.Contract, connection database.Contract, logger *servicelogger.StandardLogger,
+) error {
+	shouldMigrate, err := config.GetPropertyAsBoolWithDefault(
+		envConfig, config.PropertyNameDatabaseMigrateOnStartup, false,
+	)
+	if err != nil {
+		return err
+	}
+	if !shouldMigrate {
+		logger.Debug("Migrating at startup is turned off; skipping database migrations.")
+		return nil
+	}
+	migrator, err := database.NewMigratorFromFS(connection, migrations.FS, ".", nil)
+	if err != nil {
+		return err
+	}
+	logger.Info("Migrating database...")
+	applied, err := migrator.Up(ctx)
+	for _, migration := range applied {
+		logger.Info("Applied database migration", zap.Int64("version", migration.Version),
+			zap.String("name", migration.Name))
+	}
+	if err != nil {
+		return err
+	}
+	logger.Info("Migrated database successfully", zap.Int("applied", len(applied)))
+	return nil
+}
+
+// subscribeToConfigurationChanges registers the handlers that apply configuration changes while the service is
+// running so the log level and the feature flag polling interval can be adjusted without a restart.
+func subscribeToConfigurationChanges(
+	ctx context.Context, envConfig *config.CompositeConfig, secrets *config.SecretPropertyResolver,
+	rateLimiter *ratelimit.Limiter, logger *servicelogger.StandardLogger,
+) {
+	envConfig.Subscribe(config.PropertyNameLogLevel, func(change config.PropertyChange) {
+		if err := applyLogLevelFromConfig(envConfig, logger); err != nil {
+			logger.Error("Cannot apply log level from configuration", zap.Error(err))
+			return
+		}
+		logger.Info("Log level changed", zap.String("level", logger.Level().String()))
+	})
+	envConfig.Subscribe(config.PropertyNameFeatureFlagPollingInterval, func(change config.PropertyChange) {
+		// The DevCycle client cannot change its polling interval so a new provider replaces the current one
+		provider, readyChan, err := connectToFeatureFlagServiceFromConfig(ctx, envConfig, secrets)
+		if err != nil {
+			logger.Error("Cannot recreate feature flag provider", zap.Error(err))
+			return
+		}
+		go func() {
+			<-readyChan
+		}()
+		_, _, err = feature.RegisterOpenFeatureProvider(ctx, feature.DomainNameFeatureFlags, provider)
+		if err != nil {
+			logger.Error("Cannot register recreated feature flag provider with OpenFeature", zap.Error(err))
+			return
+		}
+		logger.Info("Feature flag polling interval changed", zap.String("interval", change.NewValue))
+	})
+	rateLimitProperties := []config.PropertyName{
+		config.PropertyNameRateLimitAlgorithm,
+		config.PropertyNameRateLimitBurst,
+		config.PropertyNameRateLimitRequests,
+		config.PropertyNameRateLimitWindow,
+	}
+	for _, property := range rateLimitProperties {
+		envConfig.Subscribe(property, func(change config.PropertyChange) {
+			rule, err := getRateLimitRuleFromConfig(envConfig)
+			if err == nil {
+				err = rateLimiter.SetRule(rule)
+			}
+			if err != nil {
+				logger.Error("Cannot apply rate limit from configuration", zap.Error(err))
+				return
+			}
+			logger.Info("Rate limit changed", zap.Any("rule", rule))
+		})
+	}
+}
+
+// watchConfiguration reloads the configuration sources at the interval given by the reload interval property. Does
+// nothing if the interval has not been set.
+func watchConfiguration(
+	ctx context.Context, envConfig *config.CompositeConfig, logger *servicelogger.StandardLogger,
+) error {
+	interval, err := config.GetPropertyAsDurationWithDefault(envConfig, config.PropertyNameConfigReloadInterval, 0)
+	if err != nil {
+		return err
+	}
+	if interval <= 0 {
+		logger.Debug("No configuration reload interval set; skipping configuration watcher.")
+		return nil
+	}
+	go func() {
+		logger.Debug("Starting configuration watcher...", zap.Duration("interval", interval))
+		err := envConfig.Watch(ctx, interval, func(err error) {
+			logger.Error("Configuration reload rejected; keeping current values", zap.Error(err))
+		})
+		logger.Debug("Configuration watcher stopped", zap.Error(err))
+	}()
+	return nil
+}
+
 // pumpEventBus pumps events from the provided event bus in its own goroutine.
 func pumpEventBus(ctx context.Context, eventBus work.BusPumperContract, debugLogger servicelogger.DebugContract) {
 	go func(ctx context.Context, bus work.BusPumperContract, logger servicelogger.DebugContract) {
@@ -187,80 +427,84 @@ func pumpMailBus(ctx context.Context, mailBus work.BusPumperContract, debugLogge
 	}(ctx, mailBus, debugLogger)
 }
 
-// readSecretFromFileOrEnvFallback attempts to read a secret from a file path specified in the configuration. If the
-// file path property does not exist or is otherwise empty, it falls back to reading the secret directly from the
-// environment configuration. It returns the resolved secret, a boolean indicating whether the secret was found, and
-// any error encountered during the process.
-func readSecretFromFileOrEnvFallback(
-	filePathPropertyName config.PropertyName, envPropertyName config.PropertyName, envConfig config.Contract,
-) (string, bool, error) {
-	// Resolve the secret from either a file path or the direct property
-	var secret string
-	secretFilePath, exists := envConfig.GetProperty(filePathPropertyName)
-	if exists && secretFilePath != "" {
-		secretBytes, err := os.ReadFile(secretFilePath)
-		if err != nil {
-			return "", exists, fmt.Errorf("Cannot read secret from file path '%s': %v", secretFilePath, err)
-		}
-		secret = string(secretBytes)
-	} else {
-		// Secret being completely blank regardless of existence is also a valid scenario
-		secret, exists = envConfig.GetProperty(envPropertyName)
-	}
-	return secret, exists, nil
-}
-
 func main() {
-	var envConfig config.Contract
-	var err error
-
-	// Determine whether to load environment configuration from a file or directly from environment variables
-	shouldLoadFromFileStr, exists := os.LookupEnv(string(config.PropertyNameLoadEnvFromFile))
-	shouldLoadFromFile, _ := strconv.ParseBool(shouldLoadFromFileStr)
-	if exists && !shouldLoadFromFile {
-		envConfig, err = config.LoadEnvironmentConfiguration()
-		if err != nil {
-			err = fmt.Errorf("%w: [environment-based]", err)
-		}
-	} else {
-		envConfig, err = config.LoadFileConfiguration()
-		if err != nil {
-			err = fmt.Errorf("%w: [file-based]", err)
-		}
-	}
+	// Load the layered configuration from the defaults, .env file(s), process environment, and command-line flags
+	envConfig, err := config.LoadCompositeConfigurationWithArguments(os.Args[1:], config.DefaultConfigurationFilePath)
 	if err != nil {
 		log.Fatalln("Cannot load environment configuration: ", err)
 	}
 
 	// Create the standard logger
-	isDebugModeActiveStr, _ := envConfig.GetProperty(config.PropertyNameDebugMode)
-	isDebugModeActive, _ := strconv.ParseBool(isDebugModeActiveStr)
+	isDebugModeActive, err := config.GetPropertyAsBoolWithDefault(envConfig, config.PropertyNameDebugMode, false)
+	if err != nil {
+		log.Fatalln("Cannot read debug mode from configuration: ", err)
+	}
 	logger, err := servicelogger.NewStandardLogger(isDebugModeActive)
 	if err != nil {
 		log.Fatalln("Cannot create standard logger: ", err)
 	}
+	if err = applyLogLevelFromConfig(envConfig, logger); err != nil {
+		logger.Fatal(fmt.Sprintf("Cannot apply log level from configuration: %v", err))
+	}
+	logger.Debug("Configuration loaded", zap.Strings("layers", envConfig.GetLayerNames()),
+		zap.Any("properties", config.GetRedactedProperties(envConfig)),
+		zap.Any("sources", envConfig.GetAllPropertySources()))
+
+	// Set up the secret resolver used to read passwords and keys from files, secret stores, or the configuration
+	ctx := context.Background()
+	secrets, err := config.NewSecretPropertyResolverFromConfig(envConfig)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("Cannot set up secret resolver: %v", err))
+	}
 
 	// Create the database connection here
 	logger.Info("Connecting to database...")
-	_, err = connectToDatabaseFromConfig(envConfig, isDebugModeActive)
+	databaseConnection, err := connectToDatabaseFromConfig(ctx, envConfig, secrets, isDebugModeActive)
 	if err != nil {
 		logger.Fatal(fmt.Sprintf("Cannot connect to database: %v", err))
 	}
 	logger.Info("Connected to database successfully")
 
+	// Apply any pending database migrations before anything can use the schema
+	if err = migrateDatabaseFromConfig(ctx, envConfig, databaseConnection, logger); err != nil {
+		logger.Fatal(fmt.Sprintf("Cannot migrate database: %v", err))
+	}
+
+	// Create the registry holding the metrics served for scraping
+	metricsRegistry := metrics.NewRegistry()
+	poolStatsCollector, err := database.NewPoolStatsCollector(databaseConnection, "postgres")
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("Cannot create database pool metrics: %v", err))
+	}
+	if err = metricsRegistry.Register(poolStatsCollector); err != nil {
+		logger.Fatal(fmt.Sprintf("Cannot register database pool metrics: %v", err))
+	}
+
 	// Create the cache connection here
 	logger.Info("Connecting to cache...")
-	ctx := context.Background()
-	_, err = connectToCacheFromConfig(ctx, envConfig, isDebugModeActive, logger)
+	cacheImplementation, err := connectToCacheFromConfig(ctx, envConfig, secrets, metricsRegistry, isDebugModeActive,
+		logger)
 	if err != nil {
 		logger.Fatal(fmt.Sprintf("Cannot connect to cache: %v", err))
 	}
 	logger.Info("Connected to cache successfully")
 
+	// Create the rate limiter for incoming gRPC calls; it allows every call while the configured limit is zero
+	rateLimiter, err := createRateLimiterFromConfig(envConfig, cacheImplementation, logger)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("Cannot create rate limiter: %v", err))
+	}
+
 	// Create a cancellable context for the event and mail bus processors
 	cancelCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	// Watch the configuration sources so changes can be applied without a restart
+	subscribeToConfigurationChanges(cancelCtx, envConfig, secrets, rateLimiter, logger)
+	if err = watchConfiguration(cancelCtx, envConfig, logger); err != nil {
+		logger.Fatal(fmt.Sprintf("Cannot watch configuration: %v", err))
+	}
+
 	// Start the event bus processor with a single registered default handler
 	eventBus := event.NewBus(work.NewConcurrentBus())
 	err = eventBus.RegisterDefaultHandler()
@@ -279,7 +523,7 @@ func main() {
 
 	// Set up the feature flag provider to work with OpenFeature; in our case, we're using DevCycle
 	logger.Info("Setting up feature flag provider...")
-	featureFlagProvider, devCycleReadyChan, err := connectToFeatureFlagServiceFromConfig(envConfig)
+	featureFlagProvider, devCycleReadyChan, err := connectToFeatureFlagServiceFromConfig(ctx, envConfig, secrets)
 	if err != nil {
 		logger.Fatal(fmt.Sprintf("Cannot set up feature flag provider from DevCycle client: %v", err))
 	}
@@ -310,8 +554,16 @@ func main() {
 		logger.Fatal(fmt.Sprintf("Failed to listen: %v", err))
 	}
 
-	// Create a gRPC server object and liveness server object, then attach them
-	grpcServer := grpc.NewServer()
+	// Create a rate-limited gRPC server object and liveness server object, then attach them
+	trustedProxies, err := config.GetPropertyAsIntWithDefault(envConfig, config.PropertyNameRateLimitTrustedProxies, 0)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("Cannot read rate limiting configuration: %v", err))
+	}
+	rateLimitKeyFunc := ratelimit.NewPeerKeyFunc(trustedProxies)
+	grpcServer := grpc.NewServer(
+		grpc.ChainStreamInterceptor(ratelimit.StreamServerInterceptor(rateLimiter, rateLimitKeyFunc)),
+		grpc.ChainUnaryInterceptor(ratelimit.UnaryServerInterceptor(rateLimiter, rateLimitKeyFunc)),
+	)
 	livenessServer := server.NewLivenessServer(service.NewLivenessService())
 	server.RegisterLivenessServer(grpcServer, livenessServer)
 
@@ -338,6 +590,19 @@ func main() {
 		logger.Fatal(fmt.Sprintf("Failed to register gateway: %v", err))
 	}
 
+	// Serve the metrics alongside the gateway endpoints so they can be scraped
+	metricsPath, _ := envConfig.GetProperty(config.PropertyNameMetricsPath)
+	if metricsPath != "" {
+		metricsHandler := metrics.Handler(metricsRegistry)
+		err = gwmux.HandlePath(gohttp.MethodGet, metricsPath,
+			func(w gohttp.ResponseWriter, r *gohttp.Request, _ map[string]string) {
+				metricsHandler.ServeHTTP(w, r)
+			})
+		if err != nil {
+			logger.Fatal(fmt.Sprintf("Failed to register metrics endpoint: %v", err))
+		}
+	}
+
 	gwServer := &gohttp.Server{
 		Addr:    fmt.Sprintf(":%s", httpPort),
 		Handler: gwmux,
@@ -5,24 +5,98 @@ import (
 	"time"
 )
 
+// Driver represents the name of a cache implementation that can be selected through configuration.
+type Driver string
+
+const (
+	// DriverMemory selects the in-process Memory cache.
+	DriverMemory Driver = "memory"
+
+	// DriverRedis selects the Redis cache.
+	DriverRedis Driver = "redis"
+
+	// DriverTiered selects the Tiered cache, which puts an in-process Memory cache in front of the Redis cache.
+	DriverTiered Driver = "tiered"
+)
+
+const (
+	// TTLKeyNotFound is the duration returned by TTL() when the key does not exist.
+	TTLKeyNotFound time.Duration = -2
+
+	// TTLNoExpiry is the duration returned by TTL() when the key exists but never expires.
+	TTLNoExpiry time.Duration = -1
+)
+
 // Contract represents a generic interface for a caching mechanism.
 type Contract interface {
 	// Close closes the connection to the cache.
 	Close() error
 
+	// Decrement atomically decrements the integer value associated with the given key by the delta and returns the
+	// new value. A missing key is treated as zero.
+	Decrement(ctx context.Context, key string, delta int64) (int64, error)
+
+	// DecrementWithTTL performs the same operation as Decrement but also applies the time-to-live (TTL) duration if
+	// the key does not already expire.
+	DecrementWithTTL(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)
+
 	// Delete removes the item associated with the given key from the cache.
 	Delete(ctx context.Context, key string) (int64, error)
 
+	// DeleteMany removes the items associated with the given keys from the cache. The integer return value indicates
+	// the number of items that were deleted.
+	DeleteMany(ctx context.Context, keys ...string) (int64, error)
+
 	// Exists checks if an item with the given key exists in the cache.
 	Exists(ctx context.Context, key string) (bool, error)
 
+	// Expire sets the time-to-live (TTL) duration of the item associated with the given key. A non-positive TTL
+	// removes the item. Returns whether the key exists.
+	Expire(ctx context.Context, key string, ttl time.Duration) (bool, error)
+
 	// Get retrieves the item associated with the given key from the cache.
 	Get(ctx context.Context, key string) ([]byte, error)
 
+	// GetAndDelete atomically retrieves and removes the item associated with the given key from the cache. If the key
+	// could not be found, this method returns nil.
+	GetAndDelete(ctx context.Context, key string) ([]byte, error)
+
+	// GetMany retrieves the items associated with the given keys from the cache. Keys that could not be found are
+	// omitted from the returned map.
+	GetMany(ctx context.Context, keys ...string) (map[string][]byte, error)
+
+	// Increment atomically increments the integer value associated with the given key by the delta and returns the
+	// new value. A missing key is treated as zero.
+	Increment(ctx context.Context, key string, delta int64) (int64, error)
+
+	// IncrementWithTTL performs the same operation as Increment but also applies the time-to-live (TTL) duration if
+	// the key does not already expire.
+	IncrementWithTTL(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)
+
+	// Scan returns a page of the keys that match the glob pattern (see MatchPattern), examining roughly count keys,
+	// along with the cursor from which to continue. Iteration starts with a zero cursor and is complete once the
+	// returned cursor is zero. Keys that exist for the whole iteration are returned at least once; keys added or
+	// removed during it may or may not be returned. A blank pattern matches every key and a non-positive count uses
+	// DefaultScanCount.
+	Scan(ctx context.Context, cursor uint64, pattern string, count int64) ([]string, uint64, error)
+
 	// Set stores the given value associated with the given key in the cache.
 	Set(ctx context.Context, key string, value []byte) error
 
+	// SetIfNotExists stores the given value associated with the given key in the cache along with a time-to-live (TTL)
+	// duration, but only if the key does not already exist. A zero TTL means the item never expires. Returns whether
+	// the value was stored.
+	SetIfNotExists(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+
+	// SetMany stores each of the given values associated with its key in the cache along with a time-to-live (TTL)
+	// duration. A zero TTL means the items never expire.
+	SetMany(ctx context.Context, values map[string][]byte, ttl time.Duration) error
+
 	// SetWithTTL stores the given value associated with the given key in the cache along with a time-to-live (TTL)
 	// duration.
 	SetWithTTL(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// TTL returns the remaining time-to-live (TTL) duration of the item associated with the given key. Returns
+	// TTLNoExpiry if the item never expires and TTLKeyNotFound if the key does not exist.
+	TTL(ctx context.Context, key string) (time.Duration, error)
 }
@@ -0,0 +1,140 @@
+package cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec represents a generic interface for converting typed values to and from the bytes stored within a cache.
+type Codec[T any] interface {
+	// Decode converts the bytes read from the cache back into a value.
+	Decode(data []byte) (T, error)
+
+	// Encode converts the value into the bytes to store within the cache.
+	Encode(value T) ([]byte, error)
+}
+
+// JSONCodec encodes values as JSON documents.
+type JSONCodec[T any] struct{}
+
+// Decode converts the JSON document back into a value.
+func (c JSONCodec[T]) Decode(data []byte) (T, error) {
+	var value T
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+// Encode converts the value into a JSON document.
+func (c JSONCodec[T]) Encode(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// GobCodec encodes values using the encoding/gob format. Only exported struct fields are encoded.
+type GobCodec[T any] struct{}
+
+// Decode converts the gob stream back into a value.
+func (c GobCodec[T]) Decode(data []byte) (T, error) {
+	var value T
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&value)
+	return value, err
+}
+
+// Encode converts the value into a gob stream.
+func (c GobCodec[T]) Encode(value T) ([]byte, error) {
+	buffer := bytes.Buffer{}
+	if err := gob.NewEncoder(&buffer).Encode(value); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// ProtoCodec encodes protocol buffer messages using the binary wire format. The type parameter is the pointer to the
+// generated message struct (e.g., *proto.LivenessResponse).
+type ProtoCodec[T proto.Message] struct{}
+
+// Decode converts the wire-format bytes back into a new message.
+func (c ProtoCodec[T]) Decode(data []byte) (T, error) {
+	var zero T
+	// generated messages report their type even through a nil pointer, which lets us allocate a new instance
+	value, ok := zero.ProtoReflect().Type().New().Interface().(T)
+	if !ok {
+		return zero, fmt.Errorf("cannot allocate protobuf message of type %T", zero)
+	}
+	if err := proto.Unmarshal(data, value); err != nil {
+		return zero, err
+	}
+	return value, nil
+}
+
+// Encode converts the message into wire-format bytes.
+func (c ProtoCodec[T]) Encode(value T) ([]byte, error) {
+	return proto.Marshal(value)
+}
+
+// GzipCodec decorates another codec so that the encoded bytes are compressed with gzip before they are stored.
+type GzipCodec[T any] struct {
+	codec Codec[T]
+	level int
+}
+
+// Decode decompresses the data and passes the result on to the decorated codec.
+func (c *GzipCodec[T]) Decode(data []byte) (T, error) {
+	var zero T
+	if c == nil || c.codec == nil {
+		return zero, ErrCodecCannotBeNil
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return zero, err
+	}
+	defer reader.Close()
+	decompressed, err := io.ReadAll(reader)
+	if err != nil {
+		return zero, err
+	}
+	return c.codec.Decode(decompressed)
+}
+
+// Encode encodes the value with the decorated codec and compresses the result.
+func (c *GzipCodec[T]) Encode(value T) ([]byte, error) {
+	if c == nil || c.codec == nil {
+		return nil, ErrCodecCannotBeNil
+	}
+	encoded, err := c.codec.Encode(value)
+	if err != nil {
+		return nil, err
+	}
+	buffer := bytes.Buffer{}
+	writer, err := gzip.NewWriterLevel(&buffer, c.level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = writer.Write(encoded); err != nil {
+		return nil, err
+	}
+	if err = writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// NewGzipCodec returns a new codec that compresses the output of the provided codec using the given gzip compression
+// level (e.g., gzip.DefaultCompression). Returns the codec plus any error that may have occurred.
+func NewGzipCodec[T any](codec Codec[T], level int) (*GzipCodec[T], error) {
+	if codec == nil {
+		return nil, ErrCodecCannotBeNil
+	}
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidCompressionLevel, level)
+	}
+	return &GzipCodec[T]{
+		codec: codec,
+		level: level,
+	}, nil
+}
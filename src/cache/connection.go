@@ -4,5 +4,5 @@ package cache
 // to a cache.
 type CacheConnectionArguments struct {
 	// CacheIdentifier is the identifier of the cache instance depending on the implementation.
-	CacheIdentifier string
+	CacheIdentifier string `config:"CACHE_IDENTIFIER" required:"true"`
 }
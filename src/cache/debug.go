@@ -9,7 +9,8 @@ import (
 	"go.uber.org/zap"
 )
 
-// Debug represents a struct that wraps a caching mechanism with debug logging capabilities.
+// Debug represents a struct that wraps a caching mechanism with debug logging capabilities. Cached values are logged
+// by their size only, since they may contain personal data.
 type Debug struct {
 	implementation Contract
 	logger         log.DebugContract
@@ -33,20 +34,56 @@ const (
 	// CacheDebugOperationClose represents a close operation.
 	CacheDebugOperationClose CacheDebugOperation = "close"
 
+	// CacheDebugOperationDecrement represents a decrement operation.
+	CacheDebugOperationDecrement CacheDebugOperation = "decrement"
+
+	// CacheDebugOperationDecrementWithTTL represents a decrement-with-TTL operation.
+	CacheDebugOperationDecrementWithTTL CacheDebugOperation = "decrementwithttl"
+
 	// CacheDebugOperationDelete represents a delete operation.
 	CacheDebugOperationDelete CacheDebugOperation = "delete"
 
+	// CacheDebugOperationDeleteMany represents a delete-many operation.
+	CacheDebugOperationDeleteMany CacheDebugOperation = "deletemany"
+
 	// CacheDebugOperationExists represents an exists operation.
 	CacheDebugOperationExists CacheDebugOperation = "exists"
 
+	// CacheDebugOperationExpire represents an expire operation.
+	CacheDebugOperationExpire CacheDebugOperation = "expire"
+
 	// CacheDebugOperationGet represents a get operation.
 	CacheDebugOperationGet CacheDebugOperation = "get"
 
+	// CacheDebugOperationGetAndDelete represents a get-and-delete operation.
+	CacheDebugOperationGetAndDelete CacheDebugOperation = "getanddelete"
+
+	// CacheDebugOperationGetMany represents a get-many operation.
+	CacheDebugOperationGetMany CacheDebugOperation = "getmany"
+
+	// CacheDebugOperationIncrement represents an increment operation.
+	CacheDebugOperationIncrement CacheDebugOperation = "increment"
+
+	// CacheDebugOperationIncrementWithTTL represents an increment-with-TTL operation.
+	CacheDebugOperationIncrementWithTTL CacheDebugOperation = "incrementwithttl"
+
+	// CacheDebugOperationScan represents a scan operation.
+	CacheDebugOperationScan CacheDebugOperation = "scan"
+
 	// CacheDebugOperationSet represents a set operation.
 	CacheDebugOperationSet CacheDebugOperation = "set"
 
+	// CacheDebugOperationSetIfNotExists represents a set-if-not-exists operation.
+	CacheDebugOperationSetIfNotExists CacheDebugOperation = "setifnotexists"
+
+	// CacheDebugOperationSetMany represents a set-many operation.
+	CacheDebugOperationSetMany CacheDebugOperation = "setmany"
+
 	// CacheDebugOperationSetWithTTL represents a set-with-TTL operation.
 	CacheDebugOperationSetWithTTL CacheDebugOperation = "setwithttl"
+
+	// CacheDebugOperationTTL represents a TTL lookup operation.
+	CacheDebugOperationTTL CacheDebugOperation = "ttl"
 )
 
 // logAction logs a cache action with the specified operation and any additional fields.
@@ -73,6 +110,34 @@ func (d *Debug) Close() error {
 	return err
 }
 
+// Decrement atomically decrements the integer value associated with the given key by the delta and returns the new
+// value. A missing key is treated as zero.
+func (d *Debug) Decrement(ctx context.Context, key string, delta int64) (int64, error) {
+	if d == nil || d.implementation == nil {
+		return 0, nil
+	}
+	d.logAction(CacheDebugActionRequest, CacheDebugOperationDecrement,
+		zap.String("key", key), zap.Int64("delta", delta))
+	value, err := d.implementation.Decrement(ctx, key, delta)
+	d.logAction(CacheDebugActionResponse, CacheDebugOperationDecrement,
+		zap.String("key", key), zap.Any("value", value), zap.Error(err))
+	return value, err
+}
+
+// DecrementWithTTL performs the same operation as Decrement but also applies the time-to-live (TTL) duration if the
+// key does not already expire.
+func (d *Debug) DecrementWithTTL(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	if d == nil || d.implementation == nil {
+		return 0, nil
+	}
+	d.logAction(CacheDebugActionRequest, CacheDebugOperationDecrementWithTTL,
+		zap.String("key", key), zap.Int64("delta", delta), zap.Duration("ttl", ttl))
+	value, err := d.implementation.DecrementWithTTL(ctx, key, delta, ttl)
+	d.logAction(CacheDebugActionResponse, CacheDebugOperationDecrementWithTTL,
+		zap.String("key", key), zap.Any("value", value), zap.Error(err))
+	return value, err
+}
+
 // Delete destroys the item associated with the given key from the cache. The integer return value indicates the number
 // of items that were deleted.
 func (d *Debug) Delete(ctx context.Context, key string) (int64, error) {
@@ -87,6 +152,20 @@ func (d *Debug) Delete(ctx context.Context, key string) (int64, error) {
 	return count, err
 }
 
+// DeleteMany destroys the items associated with the given keys from the cache. The integer return value indicates
+// the number of items that were deleted.
+func (d *Debug) DeleteMany(ctx context.Context, keys ...string) (int64, error) {
+	if d == nil || d.implementation == nil {
+		return 0, nil
+	}
+	d.logAction(CacheDebugActionRequest, CacheDebugOperationDeleteMany,
+		zap.Strings("keys", keys))
+	count, err := d.implementation.DeleteMany(ctx, keys...)
+	d.logAction(CacheDebugActionResponse, CacheDebugOperationDeleteMany,
+		zap.Strings("keys", keys), zap.Any("value", count), zap.Error(err))
+	return count, err
+}
+
 // Exists checks if an item with the given key exists in the cache.
 func (d *Debug) Exists(ctx context.Context, key string) (bool, error) {
 	if d == nil || d.implementation == nil {
@@ -100,6 +179,20 @@ func (d *Debug) Exists(ctx context.Context, key string) (bool, error) {
 	return exists, err
 }
 
+// Expire sets the time-to-live (TTL) duration of the item associated with the given key. A non-positive TTL removes
+// the item. Returns whether the key exists.
+func (d *Debug) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	if d == nil || d.implementation == nil {
+		return false, nil
+	}
+	d.logAction(CacheDebugActionRequest, CacheDebugOperationExpire,
+		zap.String("key", key), zap.Duration("ttl", ttl))
+	exists, err := d.implementation.Expire(ctx, key, ttl)
+	d.logAction(CacheDebugActionResponse, CacheDebugOperationExpire,
+		zap.String("key", key), zap.Any("value", exists), zap.Error(err))
+	return exists, err
+}
+
 // Get retrieves the item associated with the given key from the cache. If the key could not be found, this method
 // returns nil.
 func (d *Debug) Get(ctx context.Context, key string) ([]byte, error) {
@@ -110,23 +203,130 @@ func (d *Debug) Get(ctx context.Context, key string) ([]byte, error) {
 		zap.String("key", key))
 	value, err := d.implementation.Get(ctx, key)
 	d.logAction(CacheDebugActionResponse, CacheDebugOperationGet,
+		zap.String("key", key), redactValue(value), zap.Error(err))
+	return value, err
+}
+
+// GetAndDelete atomically retrieves and removes the item associated with the given key from the cache. If the key
+// could not be found, this method returns nil.
+func (d *Debug) GetAndDelete(ctx context.Context, key string) ([]byte, error) {
+	if d == nil || d.implementation == nil {
+		return nil, nil
+	}
+	d.logAction(CacheDebugActionRequest, CacheDebugOperationGetAndDelete,
+		zap.String("key", key))
+	value, err := d.implementation.GetAndDelete(ctx, key)
+	d.logAction(CacheDebugActionResponse, CacheDebugOperationGetAndDelete,
+		zap.String("key", key), redactValue(value), zap.Error(err))
+	return value, err
+}
+
+// GetMany retrieves the items associated with the given keys from the cache. Keys that could not be found are
+// omitted from the returned map.
+func (d *Debug) GetMany(ctx context.Context, keys ...string) (map[string][]byte, error) {
+	if d == nil || d.implementation == nil {
+		return map[string][]byte{}, nil
+	}
+	d.logAction(CacheDebugActionRequest, CacheDebugOperationGetMany,
+		zap.Strings("keys", keys))
+	values, err := d.implementation.GetMany(ctx, keys...)
+	d.logAction(CacheDebugActionResponse, CacheDebugOperationGetMany,
+		zap.Strings("keys", keys), redactValues(values), zap.Error(err))
+	return values, err
+}
+
+// Implementation returns the cache wrapped by this debug cache.
+func (d *Debug) Implementation() Contract {
+	if d == nil {
+		return nil
+	}
+	return d.implementation
+}
+
+// Increment atomically increments the integer value associated with the given key by the delta and returns the new
+// value. A missing key is treated as zero.
+func (d *Debug) Increment(ctx context.Context, key string, delta int64) (int64, error) {
+	if d == nil || d.implementation == nil {
+		return 0, nil
+	}
+	d.logAction(CacheDebugActionRequest, CacheDebugOperationIncrement,
+		zap.String("key", key), zap.Int64("delta", delta))
+	value, err := d.implementation.Increment(ctx, key, delta)
+	d.logAction(CacheDebugActionResponse, CacheDebugOperationIncrement,
 		zap.String("key", key), zap.Any("value", value), zap.Error(err))
 	return value, err
 }
 
+// IncrementWithTTL performs the same operation as Increment but also applies the time-to-live (TTL) duration if the
+// key does not already expire.
+func (d *Debug) IncrementWithTTL(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	if d == nil || d.implementation == nil {
+		return 0, nil
+	}
+	d.logAction(CacheDebugActionRequest, CacheDebugOperationIncrementWithTTL,
+		zap.String("key", key), zap.Int64("delta", delta), zap.Duration("ttl", ttl))
+	value, err := d.implementation.IncrementWithTTL(ctx, key, delta, ttl)
+	d.logAction(CacheDebugActionResponse, CacheDebugOperationIncrementWithTTL,
+		zap.String("key", key), zap.Any("value", value), zap.Error(err))
+	return value, err
+}
+
+// Scan returns a page of the keys that match the glob pattern, examining roughly count keys, along with the cursor
+// from which to continue.
+func (d *Debug) Scan(ctx context.Context, cursor uint64, pattern string, count int64) ([]string, uint64, error) {
+	if d == nil || d.implementation == nil {
+		return nil, 0, nil
+	}
+	d.logAction(CacheDebugActionRequest, CacheDebugOperationScan,
+		zap.Uint64("cursor", cursor), zap.String("pattern", pattern), zap.Int64("count", count))
+	keys, nextCursor, err := d.implementation.Scan(ctx, cursor, pattern, count)
+	d.logAction(CacheDebugActionResponse, CacheDebugOperationScan,
+		zap.Strings("keys", keys), zap.Uint64("cursor", nextCursor), zap.Error(err))
+	return keys, nextCursor, err
+}
+
 // Set stores the given value associated with the given key in the cache.
 func (d *Debug) Set(ctx context.Context, key string, value []byte) error {
 	if d == nil || d.implementation == nil {
 		return nil
 	}
 	d.logAction(CacheDebugActionRequest, CacheDebugOperationSet,
-		zap.String("key", key), zap.Any("value", value))
+		zap.String("key", key), redactValue(value))
 	err := d.implementation.Set(ctx, key, value)
 	d.logAction(CacheDebugActionResponse, CacheDebugOperationSet,
 		zap.String("key", key), zap.Error(err))
 	return err
 }
 
+// SetIfNotExists stores the given value associated with the given key in the cache along with a time-to-live (TTL)
+// duration, but only if the key does not already exist. A zero TTL means the item never expires. Returns whether the
+// value was stored.
+func (d *Debug) SetIfNotExists(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	if d == nil || d.implementation == nil {
+		return false, nil
+	}
+	d.logAction(CacheDebugActionRequest, CacheDebugOperationSetIfNotExists,
+		zap.String("key", key), redactValue(value), zap.Duration("ttl", ttl))
+	stored, err := d.implementation.SetIfNotExists(ctx, key, value, ttl)
+	d.logAction(CacheDebugActionResponse, CacheDebugOperationSetIfNotExists,
+		zap.String("key", key), zap.Any("value", stored), zap.Error(err))
+	return stored, err
+}
+
+// SetMany stores each of the given values associated with its key in the cache along with a time-to-live (TTL)
+// duration. A zero TTL means the items never expire.
+func (d *Debug) SetMany(ctx context.Context, values map[string][]byte, ttl time.Duration) error {
+	if d == nil || d.implementation == nil {
+		return nil
+	}
+	d.logAction(CacheDebugActionRequest, CacheDebugOperationSetMany,
+		redactValues(values), zap.Duration("ttl", ttl))
+	err := d.implementation.SetMany(ctx, values, ttl)
+	d.logAction(CacheDebugActionResponse, CacheDebugOperationSetMany,
+		zap.Int("count", len(values)), zap.Error(err))
+	return err
+}
+
 // SetWithTTL stores the given value associated with the given key in the cache along with a time-to-live (TTL)
 // duration.
 func (d *Debug) SetWithTTL(ctx context.Context, key string, value []byte, ttl time.Duration) error {
@@ -134,13 +334,27 @@ func (d *Debug) SetWithTTL(ctx context.Context, key string, value []byte, ttl ti
 		return nil
 	}
 	d.logAction(CacheDebugActionRequest, CacheDebugOperationSetWithTTL,
-		zap.String("key", key), zap.Any("value", value), zap.Duration("ttl", ttl))
+		zap.String("key", key), redactValue(value), zap.Duration("ttl", ttl))
 	err := d.implementation.SetWithTTL(ctx, key, value, ttl)
 	d.logAction(CacheDebugActionResponse, CacheDebugOperationSetWithTTL,
 		zap.String("key", key), zap.Error(err))
 	return err
 }
 
+// TTL returns the remaining time-to-live (TTL) duration of the item associated with the given key. Returns
+// TTLNoExpiry if the item never expires and TTLKeyNotFound if the key does not exist.
+func (d *Debug) TTL(ctx context.Context, key string) (time.Duration, error) {
+	if d == nil || d.implementation == nil {
+		return TTLKeyNotFound, nil
+	}
+	d.logAction(CacheDebugActionRequest, CacheDebugOperationTTL,
+		zap.String("key", key))
+	ttl, err := d.implementation.TTL(ctx, key)
+	d.logAction(CacheDebugActionResponse, CacheDebugOperationTTL,
+		zap.String("key", key), zap.Duration("value", ttl), zap.Error(err))
+	return ttl, err
+}
+
 // NewDebug takes an existing cache implementation and a debug-level logger then returns a wrapper cache that
 // provides the existing implementation with debug logging capabilities.
 func NewDebug(implementation Contract, logger log.DebugContract) *Debug {
@@ -149,3 +363,21 @@ func NewDebug(implementation Contract, logger log.DebugContract) *Debug {
 		logger:         logger,
 	}
 }
+
+// redactValue returns a log field describing the cached value by its size only, so that the values themselves (which
+// may contain personal data) never reach the logs.
+func redactValue(value []byte) zap.Field {
+	if value == nil {
+		return zap.String("value", "<nil>")
+	}
+	return zap.String("value", fmt.Sprintf("[REDACTED %d bytes]", len(value)))
+}
+
+// redactValues returns a log field describing each of the cached values by its size only.
+func redactValues(values map[string][]byte) zap.Field {
+	sizes := make(map[string]string, len(values))
+	for key, value := range values {
+		sizes[key] = fmt.Sprintf("[REDACTED %d bytes]", len(value))
+	}
+	return zap.Any("value", sizes)
+}
@@ -0,0 +1,394 @@
+package cache
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// encryptedEnvelopeVersion is the version of the envelope layout written for encrypted values.
+const encryptedEnvelopeVersion byte = 1
+
+// encryptedEnvelopeMagic identifies values that were sealed by an encrypted cache.
+var encryptedEnvelopeMagic = [2]byte{0xC5, 0x45}
+
+// EncryptionKey is a struct representing an AES key used to seal cached values, along with the ID recorded within
+// every value it seals so that the value can still be opened after the key has been rotated.
+type EncryptionKey struct {
+	// ID identifies the key within sealed values. It must be between 1 and 255 bytes long.
+	ID string
+
+	// Key is the AES key, which must be 16, 24 or 32 bytes long (AES-128, AES-192 or AES-256).
+	Key []byte
+}
+
+// EncryptedOptions is a struct representing the optional behaviour of an Encrypted cache.
+type EncryptedOptions struct {
+	// AllowUnencrypted returns values that were not sealed (e.g., those stored before encryption was turned on) as they
+	// are instead of failing with ErrDecryptFailed. It should only be turned on while migrating, since anyone able to
+	// write to the cache could then inject values.
+	AllowUnencrypted bool `config:"CACHE_ENCRYPTION_ALLOW_UNENCRYPTED"`
+}
+
+// Encrypted represents a caching mechanism that seals every value with AES-GCM before passing it on to the wrapped
+// cache and opens it again when it is read, so that the values held by the cache (and any logs of them) are
+// unreadable without the keys.
+//
+// Values are sealed with the primary key and opened with whichever key is named by the key ID within the value, so
+// keys can be rotated by making a new key primary while keeping the old ones until every value sealed with them has
+// expired. Each value is bound to its cache key, so sealed values cannot be swapped between keys.
+//
+// Integer counters (Increment, Decrement and their TTL variants) cannot be sealed and are stored as they are; read
+// them with Increment(ctx, key, 0) rather than Get.
+type Encrypted struct {
+	allowUnencrypted bool
+	implementation   Contract
+	keys             map[string]cipher.AEAD
+	primaryKeyID     string
+}
+
+// Close closes the wrapped cache.
+func (e *Encrypted) Close() error {
+	if e == nil || e.implementation == nil {
+		return nil
+	}
+	return e.implementation.Close()
+}
+
+// Decrement atomically decrements the integer value associated with the given key by the delta and returns the new
+// value. A missing key is treated as zero. The value is not encrypted.
+func (e *Encrypted) Decrement(ctx context.Context, key string, delta int64) (int64, error) {
+	if e == nil || e.implementation == nil {
+		return 0, nil
+	}
+	return e.implementation.Decrement(ctx, key, delta)
+}
+
+// DecrementWithTTL performs the same operation as Decrement but also applies the time-to-live (TTL) duration if the
+// key does not already expire. The value is not encrypted.
+func (e *Encrypted) DecrementWithTTL(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	if e == nil || e.implementation == nil {
+		return 0, nil
+	}
+	return e.implementation.DecrementWithTTL(ctx, key, delta, ttl)
+}
+
+// Delete destroys the item associated with the given key from the cache. The integer return value indicates the number
+// of items that were deleted.
+func (e *Encrypted) Delete(ctx context.Context, key string) (int64, error) {
+	if e == nil || e.implementation == nil {
+		return 0, nil
+	}
+	return e.implementation.Delete(ctx, key)
+}
+
+// DeleteMany destroys the items associated with the given keys from the cache. The integer return value indicates
+// the number of items that were deleted.
+func (e *Encrypted) DeleteMany(ctx context.Context, keys ...string) (int64, error) {
+	if e == nil || e.implementation == nil {
+		return 0, nil
+	}
+	return e.implementation.DeleteMany(ctx, keys...)
+}
+
+// Exists checks if an item with the given key exists in the cache.
+func (e *Encrypted) Exists(ctx context.Context, key string) (bool, error) {
+	if e == nil || e.implementation == nil {
+		return false, nil
+	}
+	return e.implementation.Exists(ctx, key)
+}
+
+// Expire sets the time-to-live (TTL) duration of the item associated with the given key. A non-positive TTL removes
+// the item. Returns whether the key exists.
+func (e *Encrypted) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	if e == nil || e.implementation == nil {
+		return false, nil
+	}
+	return e.implementation.Expire(ctx, key, ttl)
+}
+
+// Get retrieves and opens the item associated with the given key from the cache. If the key could not be found, this
+// method returns nil. Returns ErrDecryptFailed if the item cannot be opened.
+func (e *Encrypted) Get(ctx context.Context, key string) ([]byte, error) {
+	if e == nil || e.implementation == nil {
+		return nil, nil
+	}
+	value, err := e.implementation.Get(ctx, key)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	return e.open(key, value)
+}
+
+// GetAndDelete atomically retrieves and removes the item associated with the given key from the cache, then opens it.
+// If the key could not be found, this method returns nil. Returns ErrDecryptFailed if the item cannot be opened.
+func (e *Encrypted) GetAndDelete(ctx context.Context, key string) ([]byte, error) {
+	if e == nil || e.implementation == nil {
+		return nil, nil
+	}
+	value, err := e.implementation.GetAndDelete(ctx, key)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	return e.open(key, value)
+}
+
+// GetMany retrieves and opens the items associated with the given keys from the cache. Keys that could not be found
+// are omitted from the returned map. Returns ErrDecryptFailed if any of the items cannot be opened.
+func (e *Encrypted) GetMany(ctx context.Context, keys ...string) (map[string][]byte, error) {
+	if e == nil || e.implementation == nil {
+		return map[string][]byte{}, nil
+	}
+	values, err := e.implementation.GetMany(ctx, keys...)
+	if err != nil {
+		return map[string][]byte{}, err
+	}
+	for key, value := range values {
+		if values[key], err = e.open(key, value); err != nil {
+			return map[string][]byte{}, err
+		}
+	}
+	return values, nil
+}
+
+// Implementation returns the cache wrapped by this encrypted cache.
+func (e *Encrypted) Implementation() Contract {
+	if e == nil {
+		return nil
+	}
+	return e.implementation
+}
+
+// Increment atomically increments the integer value associated with the given key by the delta and returns the new
+// value. A missing key is treated as zero. The value is not encrypted.
+func (e *Encrypted) Increment(ctx context.Context, key string, delta int64) (int64, error) {
+	if e == nil || e.implementation == nil {
+		return 0, nil
+	}
+	return e.implementation.Increment(ctx, key, delta)
+}
+
+// IncrementWithTTL performs the same operation as Increment but also applies the time-to-live (TTL) duration if the
+// key does not already expire. The value is not encrypted.
+func (e *Encrypted) IncrementWithTTL(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	if e == nil || e.implementation == nil {
+		return 0, nil
+	}
+	return e.implementation.IncrementWithTTL(ctx, key, delta, ttl)
+}
+
+// PrimaryKeyID returns the ID of the key used to seal new values.
+func (e *Encrypted) PrimaryKeyID() string {
+	if e == nil {
+		return ""
+	}
+	return e.primaryKeyID
+}
+
+// Scan returns a page of the keys that match the glob pattern, examining roughly count keys, along with the cursor
+// from which to continue. Keys are not encrypted.
+func (e *Encrypted) Scan(ctx context.Context, cursor uint64, pattern string, count int64) ([]string, uint64, error) {
+	if e == nil || e.implementation == nil {
+		return nil, 0, nil
+	}
+	return e.implementation.Scan(ctx, cursor, pattern, count)
+}
+
+// Set seals and stores the given value associated with the given key in the cache.
+func (e *Encrypted) Set(ctx context.Context, key string, value []byte) error {
+	if e == nil || e.implementation == nil {
+		return nil
+	}
+	sealed, err := e.seal(key, value)
+	if err != nil {
+		return err
+	}
+	return e.implementation.Set(ctx, key, sealed)
+}
+
+// SetIfNotExists seals and stores the given value associated with the given key in the cache along with a
+// time-to-live (TTL) duration, but only if the key does not already exist. A zero TTL means the item never expires.
+// Returns whether the value was stored.
+func (e *Encrypted) SetIfNotExists(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	if e == nil || e.implementation == nil {
+		return false, nil
+	}
+	sealed, err := e.seal(key, value)
+	if err != nil {
+		return false, err
+	}
+	return e.implementation.SetIfNotExists(ctx, key, sealed, ttl)
+}
+
+// SetMany seals and stores each of the given values associated with its key in the cache along with a time-to-live
+// (TTL) duration. A zero TTL means the items never expire.
+func (e *Encrypted) SetMany(ctx context.Context, values map[string][]byte, ttl time.Duration) error {
+	if e == nil || e.implementation == nil {
+		return nil
+	}
+	sealedValues := make(map[string][]byte, len(values))
+	for key, value := range values {
+		sealed, err := e.seal(key, value)
+		if err != nil {
+			return err
+		}
+		sealedValues[key] = sealed
+	}
+	return e.implementation.SetMany(ctx, sealedValues, ttl)
+}
+
+// SetWithTTL seals and stores the given value associated with the given key in the cache along with a time-to-live
+// (TTL) duration.
+func (e *Encrypted) SetWithTTL(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if e == nil || e.implementation == nil {
+		return nil
+	}
+	sealed, err := e.seal(key, value)
+	if err != nil {
+		return err
+	}
+	return e.implementation.SetWithTTL(ctx, key, sealed, ttl)
+}
+
+// TTL returns the remaining time-to-live (TTL) duration of the item associated with the given key. Returns
+// TTLNoExpiry if the item never expires and TTLKeyNotFound if the key does not exist.
+func (e *Encrypted) TTL(ctx context.Context, key string) (time.Duration, error) {
+	if e == nil || e.implementation == nil {
+		return TTLKeyNotFound, nil
+	}
+	return e.implementation.TTL(ctx, key)
+}
+
+// open verifies and decrypts a sealed value using the key named within it. Returns the value plus any error that may
+// have occurred.
+func (e *Encrypted) open(key string, sealed []byte) ([]byte, error) {
+	if len(sealed) < 4 || sealed[0] != encryptedEnvelopeMagic[0] || sealed[1] != encryptedEnvelopeMagic[1] {
+		if e.allowUnencrypted {
+			return sealed, nil
+		}
+		return nil, fmt.Errorf("%w: %s: value is not encrypted", ErrDecryptFailed, key)
+	}
+	if sealed[2] != encryptedEnvelopeVersion {
+		return nil, fmt.Errorf("%w: %s: unsupported envelope version %d", ErrDecryptFailed, key, sealed[2])
+	}
+	keyIDLength := int(sealed[3])
+	if len(sealed) < 4+keyIDLength {
+		return nil, fmt.Errorf("%w: %s: value is truncated", ErrDecryptFailed, key)
+	}
+	header := sealed[:4+keyIDLength]
+	keyID := string(header[4:])
+	aead, exists := e.keys[keyID]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s: unknown key ID %q", ErrDecryptFailed, key, keyID)
+	}
+	if len(sealed) < len(header)+aead.NonceSize() {
+		return nil, fmt.Errorf("%w: %s: value is truncated", ErrDecryptFailed, key)
+	}
+	nonce := sealed[len(header) : len(header)+aead.NonceSize()]
+	value, err := aead.Open(nil, nonce, sealed[len(header)+aead.NonceSize():], makeAdditionalData(key, header))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrDecryptFailed, key, err)
+	}
+	if value == nil {
+		value = []byte{}
+	}
+	return value, nil
+}
+
+// seal encrypts the value with the primary key. Returns the sealed value plus any error that may have occurred.
+func (e *Encrypted) seal(key string, value []byte) ([]byte, error) {
+	aead := e.keys[e.primaryKeyID]
+	header := []byte{encryptedEnvelopeMagic[0], encryptedEnvelopeMagic[1], encryptedEnvelopeVersion,
+		byte(len(e.primaryKeyID))}
+	header = append(header, e.primaryKeyID...)
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrEncryptFailed, key, err)
+	}
+	sealed := make([]byte, 0, len(header)+len(nonce)+len(value)+aead.Overhead())
+	sealed = append(append(sealed, header...), nonce...)
+	return aead.Seal(sealed, nonce, value, makeAdditionalData(key, header)), nil
+}
+
+// NewEncrypted returns a new cache that seals every value with AES-GCM before passing it on to the provided cache. The
+// first key is the primary key used to seal new values; every key can open values. The options may be nil. Returns
+// the encrypted cache plus any error that may have occurred.
+func NewEncrypted(implementation Contract, keys []EncryptionKey, options *EncryptedOptions) (*Encrypted, error) {
+	if implementation == nil {
+		return nil, ErrCacheCannotBeNil
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: at least one key is required", ErrInvalidEncryptionKey)
+	}
+	encrypted := &Encrypted{
+		implementation: implementation,
+		keys:           make(map[string]cipher.AEAD, len(keys)),
+		primaryKeyID:   keys[0].ID,
+	}
+	for _, key := range keys {
+		if key.ID == "" || len(key.ID) > 255 {
+			return nil, fmt.Errorf("%w: key ID must be between 1 and 255 bytes: %q", ErrInvalidEncryptionKey, key.ID)
+		}
+		if _, exists := encrypted.keys[key.ID]; exists {
+			return nil, fmt.Errorf("%w: duplicate key ID: %q", ErrInvalidEncryptionKey, key.ID)
+		}
+		block, err := aes.NewCipher(key.Key)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidEncryptionKey, key.ID, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidEncryptionKey, key.ID, err)
+		}
+		encrypted.keys[key.ID] = aead
+	}
+	if options != nil {
+		encrypted.allowUnencrypted = options.AllowUnencrypted
+	}
+	return encrypted, nil
+}
+
+// ParseEncryptionKeys parses a comma-separated list of keys in the "id:base64-key" form (e.g., "2025-06:q83v...,
+// 2025-01:Zm9v..."), with the primary key first. Keys may use standard or URL-safe base64, with or without padding.
+// Returns the keys plus any error that may have occurred.
+func ParseEncryptionKeys(value string) ([]EncryptionKey, error) {
+	keys := []EncryptionKey{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, encodedKey, found := strings.Cut(entry, ":")
+		if !found || strings.TrimSpace(id) == "" {
+			return nil, fmt.Errorf("%w: expected id:base64-key", ErrInvalidEncryptionKey)
+		}
+		key, err := decodeBase64Key(strings.TrimSpace(encodedKey))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidEncryptionKey, strings.TrimSpace(id), err)
+		}
+		keys = append(keys, EncryptionKey{ID: strings.TrimSpace(id), Key: key})
+	}
+	return keys, nil
+}
+
+// decodeBase64Key decodes a key written in standard or URL-safe base64, with or without padding.
+func decodeBase64Key(encodedKey string) ([]byte, error) {
+	encodedKey = strings.TrimRight(encodedKey, "=")
+	if strings.ContainsAny(encodedKey, "-_") {
+		return base64.RawURLEncoding.DecodeString(encodedKey)
+	}
+	return base64.RawStdEncoding.DecodeString(encodedKey)
+}
+
+// makeAdditionalData returns the data authenticated alongside a sealed value, which binds the value to its cache key
+// and to the header naming its key.
+func makeAdditionalData(key string, header []byte) []byte {
+	additionalData := make([]byte, 0, len(header)+len(key))
+	return append(append(additionalData, header...), key...)
+}
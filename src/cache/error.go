@@ -13,6 +13,17 @@ var ErrNoCacheIdentifier = errors.New("cache identifier cannot be blank")
 // an integer.
 var ErrRedisCannotParseDatabaseIDAsInteger = errors.New("cannot parse redis database ID as integer")
 
+// ErrRedisClusterNonZeroDatabase is a sentinel error representing a database ID other than zero when attempting to
+// make a Redis Cluster connection, as clusters only support the first database.
+var ErrRedisClusterNonZeroDatabase = errors.New("redis cluster only supports database 0")
+
+// ErrRedisInvalidMode is a sentinel error representing a Redis connection mode that is not supported.
+var ErrRedisInvalidMode = errors.New("invalid redis connection mode")
+
+// ErrRedisInvalidTLSConfiguration is a sentinel error describing TLS settings for a Redis connection that are
+// incomplete or whose certificates cannot be loaded.
+var ErrRedisInvalidTLSConfiguration = errors.New("invalid redis TLS configuration")
+
 // ErrRedisNoConnectionDatabaseAddr is a sentinel error representing a blank address string when attempting to make
 // a Redis cache connection.
 var ErrRedisNoConnectionAddr = errors.New("address in redis connection arguments cannot be blank")
@@ -20,3 +31,98 @@ var ErrRedisNoConnectionAddr = errors.New("address in redis connection arguments
 // ErrRedisNoConnectionArguments is a sentinel error representing a nil connection arguments pointer when attempting
 // to make a Redis cache connection.
 var ErrRedisNoConnectionArguments = errors.New("connection arguments for redis cannot be nil")
+
+// ErrRedisNoSentinelMasterName is a sentinel error representing a blank master name when attempting to make a Redis
+// Sentinel connection.
+var ErrRedisNoSentinelMasterName = errors.New("master name in redis sentinel connection arguments cannot be blank")
+
+// ErrCacheClosed is a sentinel error representing an operation attempted on a cache that has already been closed.
+var ErrCacheClosed = errors.New("cache is closed")
+
+// ErrInvalidEvictionPolicy is a sentinel error representing an eviction policy that is not supported.
+var ErrInvalidEvictionPolicy = errors.New("invalid cache eviction policy")
+
+// ErrMemoryInvalidMaxEntries is a sentinel error representing a negative maximum number of entries when attempting to
+// create an in-memory cache.
+var ErrMemoryInvalidMaxEntries = errors.New("maximum entries for memory cache cannot be negative")
+
+// ErrMemoryNoConnectionArguments is a sentinel error representing a nil connection arguments pointer when attempting
+// to create an in-memory cache.
+var ErrMemoryNoConnectionArguments = errors.New("connection arguments for memory cache cannot be nil")
+
+// ErrValueNotInteger is a sentinel error representing an attempt to increment or decrement a cached value that is not
+// an integer.
+var ErrValueNotInteger = errors.New("cached value is not an integer")
+
+// ErrValueOutOfRange is a sentinel error representing an increment or decrement that would overflow a cached integer.
+var ErrValueOutOfRange = errors.New("cached integer would overflow")
+
+// ErrCacheCannotBeNil is a sentinel error representing an attempt to use a nil cache.
+var ErrCacheCannotBeNil = errors.New("cache instance cannot be nil")
+
+// ErrCodecCannotBeNil is a sentinel error representing an attempt to use a nil cache codec.
+var ErrCodecCannotBeNil = errors.New("cache codec cannot be nil")
+
+// ErrDecodeFailed is a sentinel error representing a cached value that was found but could not be decoded. It is
+// distinct from a cache miss.
+var ErrDecodeFailed = errors.New("cannot decode cached value")
+
+// ErrEncodeFailed is a sentinel error representing a value that could not be encoded for storage within the cache.
+var ErrEncodeFailed = errors.New("cannot encode value for cache")
+
+// ErrInvalidCompressionLevel is a sentinel error representing a compression level that is not supported by the codec.
+var ErrInvalidCompressionLevel = errors.New("invalid cache compression level")
+
+// ErrLoadFailed is a sentinel error representing a failure of the function that loads a value missing from the cache.
+var ErrLoadFailed = errors.New("cannot load value for cache")
+
+// ErrLoaderFuncCannotBeNil is a sentinel error representing an attempt to load a value with a nil loader function.
+var ErrLoaderFuncCannotBeNil = errors.New("cache loader function cannot be nil")
+
+// ErrLoaderInvalidTTL is a sentinel error representing a negative TTL within the options of a cache loader.
+var ErrLoaderInvalidTTL = errors.New("cache loader TTL cannot be negative")
+
+// ErrNotFound is a sentinel error that a cache loader function returns to report that the requested value does not
+// exist at its source. The cache loader returns it to callers as well, including when it is served from the negative
+// cache.
+var ErrNotFound = errors.New("value not found")
+
+// ErrTieredInvalidL1TTL is a sentinel error representing a negative local tier TTL when attempting to create a
+// two-tier cache.
+var ErrTieredInvalidL1TTL = errors.New("local tier TTL for tiered cache cannot be negative")
+
+// ErrTieredNoConnectionArguments is a sentinel error representing a nil connection arguments pointer when attempting
+// to create a two-tier cache.
+var ErrTieredNoConnectionArguments = errors.New("connection arguments for tiered cache cannot be nil")
+
+// ErrLockInvalidRetryInterval is a sentinel error representing a negative retry interval when attempting to create a
+// locker.
+var ErrLockInvalidRetryInterval = errors.New("lock retry interval cannot be negative")
+
+// ErrLockInvalidTTL is a sentinel error representing an attempt to acquire a lock with a TTL shorter than a
+// millisecond.
+var ErrLockInvalidTTL = errors.New("lock TTL must be at least one millisecond")
+
+// ErrLockNotAcquired is a sentinel error representing a lock that could not be acquired because it is held by someone
+// else.
+var ErrLockNotAcquired = errors.New("lock not acquired")
+
+// ErrLockNotHeld is a sentinel error representing an operation on a lock that has expired, has been released or is
+// now held by someone else.
+var ErrLockNotHeld = errors.New("lock not held")
+
+// ErrNoCacheNamespace is a sentinel error representing a blank namespace when attempting to create a namespaced cache.
+var ErrNoCacheNamespace = errors.New("cache namespace cannot be blank")
+
+// ErrInvalidPattern is a sentinel error representing a key pattern that cannot be used for the requested operation.
+var ErrInvalidPattern = errors.New("invalid cache key pattern")
+
+// ErrDecryptFailed is a sentinel error representing a cached value that was found but could not be decrypted, either
+// because it was not encrypted, its key is unknown or it has been tampered with. It is distinct from a cache miss.
+var ErrDecryptFailed = errors.New("cannot decrypt cached value")
+
+// ErrEncryptFailed is a sentinel error representing a value that could not be encrypted for storage within the cache.
+var ErrEncryptFailed = errors.New("cannot encrypt value for cache")
+
+// ErrInvalidEncryptionKey is a sentinel error representing a cache encryption key that is malformed or not supported.
+var ErrInvalidEncryptionKey = errors.New("invalid cache encryption key")
@@ -0,0 +1,324 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sepulchrestudios/go-service/src/metrics"
+)
+
+const (
+	// MetricNameHits is the name of the counter of lookups that found their key.
+	MetricNameHits = "cache_hits_total"
+
+	// MetricNameMisses is the name of the counter of lookups that did not find their key.
+	MetricNameMisses = "cache_misses_total"
+
+	// MetricNameOperationDuration is the name of the histogram of operation latencies in seconds.
+	MetricNameOperationDuration = "cache_operation_duration_seconds"
+
+	// MetricNameOperationErrors is the name of the counter of operations that returned an error.
+	MetricNameOperationErrors = "cache_operation_errors_total"
+
+	// MetricNameOperations is the name of the counter of operations performed.
+	MetricNameOperations = "cache_operations_total"
+)
+
+// Instrumented represents a struct that wraps a caching mechanism and records metrics for every operation: how many
+// were performed, how many failed and how long they took, plus the number of hits and misses of every lookup. Every
+// metric is labelled with the name of the cache and the operation (e.g., "get").
+type Instrumented struct {
+	duration       *prometheus.HistogramVec
+	errors         *prometheus.CounterVec
+	hits           *prometheus.CounterVec
+	implementation Contract
+	misses         *prometheus.CounterVec
+	name           string
+	operations     *prometheus.CounterVec
+}
+
+// Close closes the wrapped cache.
+func (i *Instrumented) Close() (err error) {
+	if i == nil || i.implementation == nil {
+		return nil
+	}
+	defer i.observe(CacheDebugOperationClose, time.Now(), &err)
+	return i.implementation.Close()
+}
+
+// Decrement atomically decrements the integer value associated with the given key by the delta and returns the new
+// value. A missing key is treated as zero.
+func (i *Instrumented) Decrement(ctx context.Context, key string, delta int64) (value int64, err error) {
+	if i == nil || i.implementation == nil {
+		return 0, nil
+	}
+	defer i.observe(CacheDebugOperationDecrement, time.Now(), &err)
+	return i.implementation.Decrement(ctx, key, delta)
+}
+
+// DecrementWithTTL performs the same operation as Decrement but also applies the time-to-live (TTL) duration if the
+// key does not already expire.
+func (i *Instrumented) DecrementWithTTL(
+	ctx context.Context, key string, delta int64, ttl time.Duration,
+) (value int64, err error) {
+	if i == nil || i.implementation == nil {
+		return 0, nil
+	}
+	defer i.observe(CacheDebugOperationDecrementWithTTL, time.Now(), &err)
+	return i.implementation.DecrementWithTTL(ctx, key, delta, ttl)
+}
+
+// Delete destroys the item associated with the given key from the cache. The integer return value indicates the number
+// of items that were deleted.
+func (i *Instrumented) Delete(ctx context.Context, key string) (count int64, err error) {
+	if i == nil || i.implementation == nil {
+		return 0, nil
+	}
+	defer i.observe(CacheDebugOperationDelete, time.Now(), &err)
+	return i.implementation.Delete(ctx, key)
+}
+
+// DeleteMany destroys the items associated with the given keys from the cache. The integer return value indicates
+// the number of items that were deleted.
+func (i *Instrumented) DeleteMany(ctx context.Context, keys ...string) (count int64, err error) {
+	if i == nil || i.implementation == nil {
+		return 0, nil
+	}
+	defer i.observe(CacheDebugOperationDeleteMany, time.Now(), &err)
+	return i.implementation.DeleteMany(ctx, keys...)
+}
+
+// Exists checks if an item with the given key exists in the cache.
+func (i *Instrumented) Exists(ctx context.Context, key string) (exists bool, err error) {
+	if i == nil || i.implementation == nil {
+		return false, nil
+	}
+	defer i.observe(CacheDebugOperationExists, time.Now(), &err)
+	return i.implementation.Exists(ctx, key)
+}
+
+// Expire sets the time-to-live (TTL) duration of the item associated with the given key. A non-positive TTL removes
+// the item. Returns whether the key exists.
+func (i *Instrumented) Expire(ctx context.Context, key string, ttl time.Duration) (exists bool, err error) {
+	if i == nil || i.implementation == nil {
+		return false, nil
+	}
+	defer i.observe(CacheDebugOperationExpire, time.Now(), &err)
+	return i.implementation.Expire(ctx, key, ttl)
+}
+
+// Get retrieves the item associated with the given key from the cache. If the key could not be found, this method
+// returns nil.
+func (i *Instrumented) Get(ctx context.Context, key string) (value []byte, err error) {
+	if i == nil || i.implementation == nil {
+		return nil, nil
+	}
+	defer i.observe(CacheDebugOperationGet, time.Now(), &err)
+	value, err = i.implementation.Get(ctx, key)
+	if err == nil {
+		i.recordLookups(CacheDebugOperationGet, value != nil, 1)
+	}
+	return value, err
+}
+
+// GetAndDelete atomically retrieves and removes the item associated with the given key from the cache. If the key
+// could not be found, this method returns nil.
+func (i *Instrumented) GetAndDelete(ctx context.Context, key string) (value []byte, err error) {
+	if i == nil || i.implementation == nil {
+		return nil, nil
+	}
+	defer i.observe(CacheDebugOperationGetAndDelete, time.Now(), &err)
+	value, err = i.implementation.GetAndDelete(ctx, key)
+	if err == nil {
+		i.recordLookups(CacheDebugOperationGetAndDelete, value != nil, 1)
+	}
+	return value, err
+}
+
+// GetMany retrieves the items associated with the given keys from the cache. Keys that could not be found are
+// omitted from the returned map. Every key counts as a separate hit or miss.
+func (i *Instrumented) GetMany(ctx context.Context, keys ...string) (values map[string][]byte, err error) {
+	if i == nil || i.implementation == nil {
+		return map[string][]byte{}, nil
+	}
+	defer i.observe(CacheDebugOperationGetMany, time.Now(), &err)
+	values, err = i.implementation.GetMany(ctx, keys...)
+	if err == nil {
+		i.recordLookups(CacheDebugOperationGetMany, true, len(values))
+		i.recordLookups(CacheDebugOperationGetMany, false, max(len(keys)-len(values), 0))
+	}
+	return values, err
+}
+
+// Implementation returns the cache wrapped by this instrumented cache.
+func (i *Instrumented) Implementation() Contract {
+	if i == nil {
+		return nil
+	}
+	return i.implementation
+}
+
+// Increment atomically increments the integer value associated with the given key by the delta and returns the new
+// value. A missing key is treated as zero.
+func (i *Instrumented) Increment(ctx context.Context, key string, delta int64) (value int64, err error) {
+	if i == nil || i.implementation == nil {
+		return 0, nil
+	}
+	defer i.observe(CacheDebugOperationIncrement, time.Now(), &err)
+	return i.implementation.Increment(ctx, key, delta)
+}
+
+// IncrementWithTTL performs the same operation as Increment but also applies the time-to-live (TTL) duration if the
+// key does not already expire.
+func (i *Instrumented) IncrementWithTTL(
+	ctx context.Context, key string, delta int64, ttl time.Duration,
+) (value int64, err error) {
+	if i == nil || i.implementation == nil {
+		return 0, nil
+	}
+	defer i.observe(CacheDebugOperationIncrementWithTTL, time.Now(), &err)
+	return i.implementation.IncrementWithTTL(ctx, key, delta, ttl)
+}
+
+// Scan returns a page of the keys that match the glob pattern, examining roughly count keys, along with the cursor
+// from which to continue.
+func (i *Instrumented) Scan(
+	ctx context.Context, cursor uint64, pattern string, count int64,
+) (keys []string, nextCursor uint64, err error) {
+	if i == nil || i.implementation == nil {
+		return nil, 0, nil
+	}
+	defer i.observe(CacheDebugOperationScan, time.Now(), &err)
+	return i.implementation.Scan(ctx, cursor, pattern, count)
+}
+
+// Set stores the given value associated with the given key in the cache.
+func (i *Instrumented) Set(ctx context.Context, key string, value []byte) (err error) {
+	if i == nil || i.implementation == nil {
+		return nil
+	}
+	defer i.observe(CacheDebugOperationSet, time.Now(), &err)
+	return i.implementation.Set(ctx, key, value)
+}
+
+// SetIfNotExists stores the given value associated with the given key in the cache along with a time-to-live (TTL)
+// duration, but only if the key does not already exist. A zero TTL means the item never expires. Returns whether the
+// value was stored.
+func (i *Instrumented) SetIfNotExists(
+	ctx context.Context, key string, value []byte, ttl time.Duration,
+) (stored bool, err error) {
+	if i == nil || i.implementation == nil {
+		return false, nil
+	}
+	defer i.observe(CacheDebugOperationSetIfNotExists, time.Now(), &err)
+	return i.implementation.SetIfNotExists(ctx, key, value, ttl)
+}
+
+// SetMany stores each of the given values associated with its key in the cache along with a time-to-live (TTL)
+// duration. A zero TTL means the items never expire.
+func (i *Instrumented) SetMany(ctx context.Context, values map[string][]byte, ttl time.Duration) (err error) {
+	if i == nil || i.implementation == nil {
+		return nil
+	}
+	defer i.observe(CacheDebugOperationSetMany, time.Now(), &err)
+	return i.implementation.SetMany(ctx, values, ttl)
+}
+
+// SetWithTTL stores the given value associated with the given key in the cache along with a time-to-live (TTL)
+// duration.
+func (i *Instrumented) SetWithTTL(ctx context.Context, key string, value []byte, ttl time.Duration) (err error) {
+	if i == nil || i.implementation == nil {
+		return nil
+	}
+	defer i.observe(CacheDebugOperationSetWithTTL, time.Now(), &err)
+	return i.implementation.SetWithTTL(ctx, key, value, ttl)
+}
+
+// TTL returns the remaining time-to-live (TTL) duration of the item associated with the given key. Returns
+// TTLNoExpiry if the item never expires and TTLKeyNotFound if the key does not exist.
+func (i *Instrumented) TTL(ctx context.Context, key string) (ttl time.Duration, err error) {
+	if i == nil || i.implementation == nil {
+		return TTLKeyNotFound, nil
+	}
+	defer i.observe(CacheDebugOperationTTL, time.Now(), &err)
+	return i.implementation.TTL(ctx, key)
+}
+
+// observe records that the operation was performed, how long it took since it started, and whether it failed.
+func (i *Instrumented) observe(operation CacheDebugOperation, start time.Time, err *error) {
+	i.operations.WithLabelValues(i.name, string(operation)).Inc()
+	i.duration.WithLabelValues(i.name, string(operation)).Observe(time.Since(start).Seconds())
+	if *err != nil {
+		i.errors.WithLabelValues(i.name, string(operation)).Inc()
+	}
+}
+
+// recordLookups records the number of lookups performed by the operation that were either hits or misses.
+func (i *Instrumented) recordLookups(operation CacheDebugOperation, hit bool, count int) {
+	if count == 0 {
+		return
+	}
+	if hit {
+		i.hits.WithLabelValues(i.name, string(operation)).Add(float64(count))
+		return
+	}
+	i.misses.WithLabelValues(i.name, string(operation)).Add(float64(count))
+}
+
+// NewInstrumented returns a new cache that records metrics for every operation of the provided cache within the
+// registry, labelled with the name (e.g., the cache driver). Several caches can share a registry as long as their
+// names differ. Returns the instrumented cache plus any error that may have occurred.
+func NewInstrumented(
+	implementation Contract, registry prometheus.Registerer, name string,
+) (*Instrumented, error) {
+	if implementation == nil {
+		return nil, ErrCacheCannotBeNil
+	}
+	if registry == nil {
+		return nil, metrics.ErrRegistryCannotBeNil
+	}
+	labelNames := []string{"cache", "operation"}
+	instrumented := &Instrumented{
+		implementation: implementation,
+		name:           name,
+	}
+	var err error
+	instrumented.operations, err = metrics.RegisterCounterVec(registry, prometheus.CounterOpts{
+		Name: MetricNameOperations,
+		Help: "Number of cache operations performed.",
+	}, labelNames...)
+	if err != nil {
+		return nil, err
+	}
+	instrumented.errors, err = metrics.RegisterCounterVec(registry, prometheus.CounterOpts{
+		Name: MetricNameOperationErrors,
+		Help: "Number of cache operations that returned an error.",
+	}, labelNames...)
+	if err != nil {
+		return nil, err
+	}
+	instrumented.hits, err = metrics.RegisterCounterVec(registry, prometheus.CounterOpts{
+		Name: MetricNameHits,
+		Help: "Number of cache lookups that found their key.",
+	}, labelNames...)
+	if err != nil {
+		return nil, err
+	}
+	instrumented.misses, err = metrics.RegisterCounterVec(registry, prometheus.CounterOpts{
+		Name: MetricNameMisses,
+		Help: "Number of cache lookups that did not find their key.",
+	}, labelNames...)
+	if err != nil {
+		return nil, err
+	}
+	instrumented.duration, err = metrics.RegisterHistogramVec(registry, prometheus.HistogramOpts{
+		Name:    MetricNameOperationDuration,
+		Help:    "Latency of cache operations in seconds.",
+		Buckets: metrics.DefaultLatencyBuckets,
+	}, labelNames...)
+	if err != nil {
+		return nil, err
+	}
+	return instrumented, nil
+}
@@ -0,0 +1,212 @@
+package cache
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// loaderEnvelopeVersion is the version of the envelope layout written by the Loader.
+const loaderEnvelopeVersion byte = 1
+
+// loaderEnvelopeHeaderSize is the number of bytes that precede the value within an envelope: two magic bytes, the
+// version, the flags and the soft expiry as Unix nanoseconds.
+const loaderEnvelopeHeaderSize = 12
+
+// loaderEnvelopeFlagNegative marks an envelope that records the absence of a value.
+const loaderEnvelopeFlagNegative byte = 1
+
+// loaderEnvelopeMagic identifies values that were written by the Loader.
+var loaderEnvelopeMagic = [2]byte{0xC5, 0x4C}
+
+// LoaderFunc represents a function that loads the value for the given key from its source of truth (e.g., the
+// database) when it is missing from the cache. Returning ErrNotFound records that the value does not exist.
+type LoaderFunc func(ctx context.Context, key string) ([]byte, error)
+
+// LoaderOptions is a struct representing the optional behaviour of a Loader.
+type LoaderOptions struct {
+	// NegativeTTL is the time-to-live (TTL) duration of the marker stored when a LoaderFunc returns ErrNotFound, so
+	// that repeated lookups for a missing value do not reach the source of truth. A zero value turns negative caching
+	// off.
+	NegativeTTL time.Duration
+
+	// OnError is called with any error that cannot be returned to a caller, such as a failure to store a loaded value
+	// or a failed background refresh. It may be nil.
+	OnError func(key string, err error)
+
+	// SoftTTL is the age after which a cached value is considered stale. A stale value is still returned, but a single
+	// background refresh is started to replace it. A zero value (or one that is not shorter than the TTL passed to
+	// GetOrLoad) turns stale-while-revalidate off.
+	SoftTTL time.Duration
+}
+
+// loaderEnvelope represents a value stored within the cache by the Loader along with its metadata.
+type loaderEnvelope struct {
+	// negative describes whether the envelope records the absence of a value.
+	negative bool
+
+	// softExpiresAt is the time at which the value becomes stale, or the zero time if it never becomes stale.
+	softExpiresAt time.Time
+
+	// value is the cached value.
+	value []byte
+}
+
+// Loader implements read-through caching on top of any cache. Concurrent misses for the same key within the process
+// are collapsed into a single call to the LoaderFunc so that a hot key expiring does not cause a thundering herd
+// against the source of truth.
+//
+// Values are stored inside a small envelope that records their soft expiry, so keys populated by a Loader should only
+// be read through a Loader. Values that were written directly are still returned, but are never considered stale.
+type Loader struct {
+	cache   Contract
+	group   singleflight.Group
+	options LoaderOptions
+}
+
+// GetOrLoad returns the value associated with the given key. If the key is missing from the cache, the LoaderFunc is
+// called (at most once per key at a time within the process) and its result is stored with the given TTL before it is
+// returned. If the value is stale, it is returned immediately while it is refreshed in the background.
+//
+// Returns ErrNotFound if the LoaderFunc reported that the value does not exist, including when that result was served
+// from the negative cache. A failure to read from the cache falls back to the LoaderFunc, and a failure to write to the
+// cache is reported through LoaderOptions.OnError rather than to the caller.
+func (l *Loader) GetOrLoad(ctx context.Context, key string, ttl time.Duration, loader LoaderFunc) ([]byte, error) {
+	if l == nil || l.cache == nil {
+		return nil, ErrCacheCannotBeNil
+	}
+	if loader == nil {
+		return nil, ErrLoaderFuncCannotBeNil
+	}
+	data, err := l.cache.Get(ctx, key)
+	if err != nil {
+		l.reportError(key, err)
+	}
+	if err == nil && data != nil {
+		envelope := decodeLoaderEnvelope(data)
+		if !envelope.softExpiresAt.IsZero() && time.Now().After(envelope.softExpiresAt) {
+			l.refresh(ctx, key, ttl, loader)
+		}
+		if envelope.negative {
+			return nil, ErrNotFound
+		}
+		return envelope.value, nil
+	}
+	result := l.group.DoChan(key, func() (any, error) {
+		// the load is shared by every caller waiting on the key, so one caller giving up must not cancel it
+		return l.load(context.WithoutCancel(ctx), key, ttl, loader)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case response := <-result:
+		if response.Err != nil {
+			return nil, response.Err
+		}
+		return response.Val.([]byte), nil
+	}
+}
+
+// load calls the LoaderFunc and stores its result within the cache. Returns the loaded value plus any error that may
+// have occurred.
+func (l *Loader) load(ctx context.Context, key string, ttl time.Duration, loader LoaderFunc) ([]byte, error) {
+	value, err := loader(ctx, key)
+	if errors.Is(err, ErrNotFound) {
+		if l.options.NegativeTTL > 0 {
+			envelope := &loaderEnvelope{negative: true}
+			if err := l.cache.SetWithTTL(ctx, key, envelope.encode(), l.options.NegativeTTL); err != nil {
+				l.reportError(key, err)
+			}
+		}
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrLoadFailed, key, err)
+	}
+	if value == nil {
+		value = []byte{}
+	}
+	envelope := &loaderEnvelope{value: value}
+	if l.options.SoftTTL > 0 && (ttl <= 0 || l.options.SoftTTL < ttl) {
+		envelope.softExpiresAt = time.Now().Add(l.options.SoftTTL)
+	}
+	if err := l.cache.SetWithTTL(ctx, key, envelope.encode(), ttl); err != nil {
+		l.reportError(key, err)
+	}
+	return value, nil
+}
+
+// refresh reloads the stale value associated with the key in the background. Refreshes for the same key share the
+// singleflight group with regular loads, so at most one is in progress at a time.
+func (l *Loader) refresh(ctx context.Context, key string, ttl time.Duration, loader LoaderFunc) {
+	result := l.group.DoChan(key, func() (any, error) {
+		return l.load(context.WithoutCancel(ctx), key, ttl, loader)
+	})
+	go func() {
+		response := <-result
+		if response.Err != nil && !errors.Is(response.Err, ErrNotFound) {
+			l.reportError(key, response.Err)
+		}
+	}()
+}
+
+// reportError passes the error on to the OnError callback, if there is one.
+func (l *Loader) reportError(key string, err error) {
+	if l.options.OnError != nil {
+		l.options.OnError(key, err)
+	}
+}
+
+// NewLoader returns a new read-through loader on top of the provided cache. The options may be nil. Returns the loader
+// plus any error that may have occurred.
+func NewLoader(cache Contract, options *LoaderOptions) (*Loader, error) {
+	if cache == nil {
+		return nil, ErrCacheCannotBeNil
+	}
+	loader := &Loader{
+		cache: cache,
+	}
+	if options != nil {
+		if options.NegativeTTL < 0 || options.SoftTTL < 0 {
+			return nil, ErrLoaderInvalidTTL
+		}
+		loader.options = *options
+	}
+	return loader, nil
+}
+
+// decodeLoaderEnvelope unpacks the data read from the cache. Data that was not written by the Loader is treated as a
+// value that never becomes stale.
+func decodeLoaderEnvelope(data []byte) *loaderEnvelope {
+	if len(data) < loaderEnvelopeHeaderSize || data[0] != loaderEnvelopeMagic[0] || data[1] != loaderEnvelopeMagic[1] ||
+		data[2] != loaderEnvelopeVersion {
+		return &loaderEnvelope{value: data}
+	}
+	envelope := &loaderEnvelope{
+		negative: data[3]&loaderEnvelopeFlagNegative != 0,
+		value:    data[loaderEnvelopeHeaderSize:],
+	}
+	if softExpiresAt := int64(binary.BigEndian.Uint64(data[4:loaderEnvelopeHeaderSize])); softExpiresAt != 0 {
+		envelope.softExpiresAt = time.Unix(0, softExpiresAt)
+	}
+	return envelope
+}
+
+// encode packs the envelope into the bytes stored within the cache.
+func (e *loaderEnvelope) encode() []byte {
+	data := make([]byte, loaderEnvelopeHeaderSize, loaderEnvelopeHeaderSize+len(e.value))
+	data[0] = loaderEnvelopeMagic[0]
+	data[1] = loaderEnvelopeMagic[1]
+	data[2] = loaderEnvelopeVersion
+	if e.negative {
+		data[3] |= loaderEnvelopeFlagNegative
+	}
+	if !e.softExpiresAt.IsZero() {
+		binary.BigEndian.PutUint64(data[4:loaderEnvelopeHeaderSize], uint64(e.softExpiresAt.UnixNano()))
+	}
+	return append(data, e.value...)
+}
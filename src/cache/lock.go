@@ -0,0 +1,272 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultLockKeyPrefix is the prefix added to lock names to build their cache keys when no other prefix has been
+// provided.
+const DefaultLockKeyPrefix = "lock:"
+
+// DefaultLockRetryInterval is how often a blocking acquisition retries when no other interval has been provided.
+const DefaultLockRetryInterval time.Duration = 100 * time.Millisecond
+
+// lockReleaseScript deletes the lock key only if it still holds the token of the caller, so that a holder whose lock
+// has expired cannot release a lock that has since been acquired by someone else.
+var lockReleaseScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+end
+return 0
+`)
+
+// lockRefreshScript extends the TTL of the lock key only if it still holds the token of the caller.
+var lockRefreshScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("pexpire", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// LockerOptions is a struct representing the optional behaviour of a Locker.
+type LockerOptions struct {
+	// KeyPrefix is added to every lock name to build its cache key. Defaults to DefaultLockKeyPrefix.
+	KeyPrefix string
+
+	// RetryInterval is how often Lock() retries while the lock is held by someone else. Defaults to
+	// DefaultLockRetryInterval.
+	RetryInterval time.Duration
+}
+
+// Locker hands out distributed locks stored within a Redis cache. Each lock is identified by a name and is held by
+// whoever stored their unique token under its key, so it can be used to keep work such as scheduled jobs from running
+// on more than one instance at a time.
+type Locker struct {
+	client        redis.UniversalClient
+	keyPrefix     string
+	retryInterval time.Duration
+}
+
+// Lock blocks until the lock with the given name has been acquired or the context is done. See TryLock for how the
+// lock is held. Returns the held lock plus any error that may have occurred.
+func (l *Locker) Lock(ctx context.Context, name string, ttl time.Duration) (*Lock, error) {
+	if l == nil || l.client == nil {
+		return nil, ErrCacheCannotBeNil
+	}
+	ticker := time.NewTicker(l.retryInterval)
+	defer ticker.Stop()
+	for {
+		lock, err := l.TryLock(ctx, name, ttl)
+		if err != ErrLockNotAcquired {
+			return lock, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %s: %w", ErrLockNotAcquired, name, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// TryLock makes a single attempt to acquire the lock with the given name. The lock expires after the TTL unless it is
+// renewed, which happens automatically in the background every third of the TTL until the lock is released. Returns
+// the held lock plus any error that may have occurred; ErrLockNotAcquired means the lock is held by someone else.
+func (l *Locker) TryLock(ctx context.Context, name string, ttl time.Duration) (*Lock, error) {
+	if l == nil || l.client == nil {
+		return nil, ErrCacheCannotBeNil
+	}
+	if ttl < time.Millisecond {
+		return nil, ErrLockInvalidTTL
+	}
+	lock := &Lock{
+		client: l.client,
+		done:   make(chan struct{}),
+		key:    l.keyPrefix + name,
+		lost:   make(chan struct{}),
+		stop:   make(chan struct{}),
+		token:  rand.Text(),
+		ttl:    ttl,
+	}
+	acquired, err := l.client.SetNX(ctx, lock.key, lock.token, ttl).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, ErrLockNotAcquired
+	}
+	go lock.renew()
+	return lock, nil
+}
+
+// WithLock blocks until the lock with the given name has been acquired, runs the function and then releases the lock.
+// The context passed to the function is cancelled if the lock is lost while the function is running. Returns the error
+// returned by the function, or any error that occurred while acquiring or releasing the lock.
+func (l *Locker) WithLock(
+	ctx context.Context, name string, ttl time.Duration, fn func(ctx context.Context) error,
+) (err error) {
+	lock, err := l.Lock(ctx, name, ttl)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if releaseErr := lock.Release(context.WithoutCancel(ctx)); err == nil {
+			err = releaseErr
+		}
+	}()
+	fnCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-lock.Lost():
+			cancel()
+		case <-fnCtx.Done():
+		}
+	}()
+	return fn(fnCtx)
+}
+
+// NewLocker returns a new locker that stores its locks within the provided Redis cache. The options may be nil.
+// Returns the locker plus any error that may have occurred.
+func NewLocker(redisCache *Redis, options *LockerOptions) (*Locker, error) {
+	if redisCache == nil || redisCache.Client() == nil {
+		return nil, ErrCacheCannotBeNil
+	}
+	locker := &Locker{
+		client:        redisCache.Client(),
+		keyPrefix:     DefaultLockKeyPrefix,
+		retryInterval: DefaultLockRetryInterval,
+	}
+	if options != nil {
+		if options.RetryInterval < 0 {
+			return nil, ErrLockInvalidRetryInterval
+		}
+		if options.KeyPrefix != "" {
+			locker.keyPrefix = options.KeyPrefix
+		}
+		if options.RetryInterval > 0 {
+			locker.retryInterval = options.RetryInterval
+		}
+	}
+	return locker, nil
+}
+
+// Lock represents a distributed lock that is currently held. It also contains a mutex so it should ONLY be passed
+// around by-reference and never by-value.
+type Lock struct {
+	client   redis.UniversalClient
+	done     chan struct{}
+	key      string
+	lost     chan struct{}
+	lostOnce sync.Once
+	mu       sync.Mutex
+	released bool
+	stop     chan struct{}
+	token    string
+	ttl      time.Duration
+}
+
+// Key returns the cache key under which the lock is stored.
+func (l *Lock) Key() string {
+	if l == nil {
+		return ""
+	}
+	return l.key
+}
+
+// Lost returns a channel that is closed if the lock is lost before it is released, either because it could not be
+// renewed before it expired or because it was taken over by someone else.
+func (l *Lock) Lost() <-chan struct{} {
+	if l == nil {
+		return nil
+	}
+	return l.lost
+}
+
+// Refresh extends the lock so that it expires one TTL from now. Returns ErrLockNotHeld if the lock has expired or is
+// now held by someone else.
+func (l *Lock) Refresh(ctx context.Context) error {
+	if l == nil || l.client == nil {
+		return ErrLockNotHeld
+	}
+	result, err := lockRefreshScript.Run(ctx, l.client, []string{l.key}, l.token, l.ttl.Milliseconds()).Int64()
+	if err != nil {
+		return err
+	}
+	if result == 0 {
+		l.markLost()
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// Release stops renewing the lock and removes it so that it can be acquired by someone else. Returns ErrLockNotHeld if
+// the lock had already expired or been taken over by someone else.
+func (l *Lock) Release(ctx context.Context) error {
+	if l == nil || l.client == nil {
+		return ErrLockNotHeld
+	}
+	// ensure we don't get a collision if two or more goroutines try to write concurrently
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.released {
+		return ErrLockNotHeld
+	}
+	l.released = true
+	close(l.stop)
+	<-l.done
+	result, err := lockReleaseScript.Run(ctx, l.client, []string{l.key}, l.token).Int64()
+	if err != nil {
+		return err
+	}
+	if result == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// Token returns the unique token that identifies this holder of the lock.
+func (l *Lock) Token() string {
+	if l == nil {
+		return ""
+	}
+	return l.token
+}
+
+// markLost closes the lost channel if it has not been closed already.
+func (l *Lock) markLost() {
+	l.lostOnce.Do(func() {
+		close(l.lost)
+	})
+}
+
+// renew refreshes the lock every third of its TTL until it is released. Failed refreshes are retried at the same
+// interval, and the lock is marked as lost once it has been refused or the TTL has passed without a refresh.
+func (l *Lock) renew() {
+	defer close(l.done)
+	ticker := time.NewTicker(max(l.ttl/3, time.Millisecond))
+	defer ticker.Stop()
+	lastRefresh := time.Now()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case now := <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), l.ttl/3+time.Millisecond)
+			err := l.Refresh(ctx)
+			cancel()
+			switch {
+			case err == nil:
+				lastRefresh = now
+			case err == ErrLockNotHeld || now.Sub(lastRefresh) >= l.ttl:
+				l.markLost()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,787 @@
+package cache
+
+import (
+	"cmp"
+	"container/heap"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// EvictionPolicy represents the strategy used to choose which item is removed when a bounded cache is full.
+type EvictionPolicy string
+
+const (
+	// EvictionPolicyLFU evicts the least frequently used item, breaking ties with the least recently used item.
+	EvictionPolicyLFU EvictionPolicy = "lfu"
+
+	// EvictionPolicyLRU evicts the least recently used item.
+	EvictionPolicyLRU EvictionPolicy = "lru"
+)
+
+// DefaultMemoryCleanupInterval is the interval at which expired items are removed from an in-memory cache when no
+// other interval has been provided.
+const DefaultMemoryCleanupInterval time.Duration = time.Minute
+
+// MemoryConnectionArguments is a struct representing the properties expected when creating an in-memory cache.
+//
+// The struct tags allow the arguments to be filled from the service configuration with config.Bind().
+type MemoryConnectionArguments struct {
+	// CleanupInterval is the interval at which expired items are removed in the background. Expired items are never
+	// returned regardless of this setting. A zero value uses DefaultMemoryCleanupInterval and a negative value turns
+	// background cleanup off.
+	CleanupInterval time.Duration
+
+	// EvictionPolicy decides which item is removed when the cache is full. Defaults to EvictionPolicyLRU.
+	EvictionPolicy EvictionPolicy `config:"CACHE_EVICTION_POLICY"`
+
+	// MaxEntries is the maximum number of items held by the cache. A value of zero means the size is unbounded.
+	MaxEntries int `config:"CACHE_MAX_ENTRIES"`
+}
+
+// memoryItem represents a single item held by an in-memory cache.
+type memoryItem struct {
+	// expiresAt is the time at which the item expires, or the zero time if it never expires.
+	expiresAt time.Time
+
+	// expiryIndex is the position of the item within the expiry heap, or -1 if the item never expires.
+	expiryIndex int
+
+	// frequency is the number of times the item has been written or read.
+	frequency uint64
+
+	// hash is the hash of the key, which determines the position of the item within a scan.
+	hash uint64
+
+	// index is the position of the item within the eviction heap.
+	index int
+
+	// key is the key associated with the item.
+	key string
+
+	// lastUsed is the value of the cache clock when the item was last written or read.
+	lastUsed uint64
+
+	// value is a private copy of the stored value.
+	value []byte
+}
+
+// isExpired returns whether the item has expired as of the given time.
+func (i *memoryItem) isExpired(now time.Time) bool {
+	return !i.expiresAt.IsZero() && !now.Before(i.expiresAt)
+}
+
+// memoryEvictionHeap orders the items of an in-memory cache so that the next item to evict is always at the top.
+type memoryEvictionHeap struct {
+	items  []*memoryItem
+	policy EvictionPolicy
+}
+
+// Len returns the number of items within the heap.
+func (h *memoryEvictionHeap) Len() int {
+	return len(h.items)
+}
+
+// Less returns whether the item at index i should be evicted before the item at index j.
+func (h *memoryEvictionHeap) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	if h.policy == EvictionPolicyLFU && a.frequency != b.frequency {
+		return a.frequency < b.frequency
+	}
+	return a.lastUsed < b.lastUsed
+}
+
+// Pop removes and returns the last item of the heap.
+func (h *memoryEvictionHeap) Pop() any {
+	last := len(h.items) - 1
+	item := h.items[last]
+	h.items[last] = nil
+	h.items = h.items[:last]
+	item.index = -1
+	return item
+}
+
+// Push appends the item to the heap.
+func (h *memoryEvictionHeap) Push(x any) {
+	item := x.(*memoryItem)
+	item.index = len(h.items)
+	h.items = append(h.items, item)
+}
+
+// Swap swaps the items at the given indexes.
+func (h *memoryEvictionHeap) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].index = i
+	h.items[j].index = j
+}
+
+// memoryExpiryHeap orders the expiring items of an in-memory cache so that the item that expires first is always at
+// the top.
+type memoryExpiryHeap struct {
+	items []*memoryItem
+}
+
+// Len returns the number of items within the heap.
+func (h *memoryExpiryHeap) Len() int {
+	return len(h.items)
+}
+
+// Less returns whether the item at index i expires before the item at index j.
+func (h *memoryExpiryHeap) Less(i, j int) bool {
+	return h.items[i].expiresAt.Before(h.items[j].expiresAt)
+}
+
+// Pop removes and returns the last item of the heap.
+func (h *memoryExpiryHeap) Pop() any {
+	last := len(h.items) - 1
+	item := h.items[last]
+	h.items[last] = nil
+	h.items = h.items[:last]
+	item.expiryIndex = -1
+	return item
+}
+
+// Push appends the item to the heap.
+func (h *memoryExpiryHeap) Push(x any) {
+	item := x.(*memoryItem)
+	item.expiryIndex = len(h.items)
+	h.items = append(h.items, item)
+}
+
+// Swap swaps the items at the given indexes.
+func (h *memoryExpiryHeap) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].expiryIndex = i
+	h.items[j].expiryIndex = j
+}
+
+// scanHashHeap is a max-heap of key hashes used to select the smallest hashes for a page of a scan.
+type scanHashHeap []uint64
+
+// Len returns the number of hashes within the heap.
+func (h *scanHashHeap) Len() int {
+	return len(*h)
+}
+
+// Less returns whether the hash at index i is larger than the hash at index j, so that the largest is at the top.
+func (h *scanHashHeap) Less(i, j int) bool {
+	return (*h)[i] > (*h)[j]
+}
+
+// Pop removes and returns the last hash of the heap.
+func (h *scanHashHeap) Pop() any {
+	last := len(*h) - 1
+	hash := (*h)[last]
+	*h = (*h)[:last]
+	return hash
+}
+
+// Push appends the hash to the heap.
+func (h *scanHashHeap) Push(x any) {
+	*h = append(*h, x.(uint64))
+}
+
+// Swap swaps the hashes at the given indexes.
+func (h *scanHashHeap) Swap(i, j int) {
+	(*h)[i], (*h)[j] = (*h)[j], (*h)[i]
+}
+
+// Memory represents an in-process caching mechanism that is safe for concurrent use. Items can expire with a TTL and
+// the number of items can be bounded, in which case items are evicted according to the eviction policy. It also
+// contains a mutex so it should ONLY be passed around by-reference and never by-value.
+type Memory struct {
+	clock      uint64
+	closed     bool
+	evictions  *memoryEvictionHeap
+	expiries   *memoryExpiryHeap
+	items      map[string]*memoryItem
+	maxEntries int
+	mu         sync.Mutex
+	stop       chan struct{}
+}
+
+// Clear removes every item from the cache.
+func (m *Memory) Clear() {
+	if m == nil {
+		return
+	}
+	// ensure we don't get a collision if two or more goroutines try to write concurrently
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	m.items = map[string]*memoryItem{}
+	m.evictions.items = []*memoryItem{}
+	m.expiries.items = []*memoryItem{}
+}
+
+// Close stops the background cleanup and removes every item from the cache. Any further operations return
+// ErrCacheClosed.
+func (m *Memory) Close() error {
+	if m == nil {
+		return nil
+	}
+	// ensure we don't get a collision if two or more goroutines try to write concurrently
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	if m.stop != nil {
+		close(m.stop)
+	}
+	m.items = map[string]*memoryItem{}
+	m.evictions.items = nil
+	m.expiries.items = nil
+	return nil
+}
+
+// Decrement atomically decrements the integer value associated with the given key by the delta and returns the new
+// value. A missing key is treated as zero.
+func (m *Memory) Decrement(ctx context.Context, key string, delta int64) (int64, error) {
+	return m.IncrementWithTTL(ctx, key, -delta, 0)
+}
+
+// DecrementWithTTL performs the same operation as Decrement but also applies the time-to-live (TTL) duration if the
+// key does not already expire.
+func (m *Memory) DecrementWithTTL(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	return m.IncrementWithTTL(ctx, key, -delta, ttl)
+}
+
+// Delete destroys the item associated with the given key from the cache. The integer return value indicates the number
+// of items that were deleted.
+func (m *Memory) Delete(ctx context.Context, key string) (int64, error) {
+	if m == nil {
+		return 0, nil
+	}
+	// ensure we don't get a collision if two or more goroutines try to write concurrently
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return 0, ErrCacheClosed
+	}
+	if m.getLiveItem(key, time.Now()) == nil {
+		return 0, nil
+	}
+	m.removeItem(m.items[key])
+	return 1, nil
+}
+
+// DeleteMany destroys the items associated with the given keys from the cache. The integer return value indicates
+// the number of items that were deleted.
+func (m *Memory) DeleteMany(ctx context.Context, keys ...string) (int64, error) {
+	if m == nil {
+		return 0, nil
+	}
+	// ensure we don't get a collision if two or more goroutines try to write concurrently
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return 0, ErrCacheClosed
+	}
+	now := time.Now()
+	var count int64
+	for _, key := range keys {
+		if item := m.getLiveItem(key, now); item != nil {
+			m.removeItem(item)
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Exists checks if an item with the given key exists in the cache.
+func (m *Memory) Exists(ctx context.Context, key string) (bool, error) {
+	if m == nil {
+		return false, nil
+	}
+	// ensure we don't get a collision if two or more goroutines try to read concurrently
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return false, ErrCacheClosed
+	}
+	return m.getLiveItem(key, time.Now()) != nil, nil
+}
+
+// Expire sets the time-to-live (TTL) duration of the item associated with the given key. A non-positive TTL removes
+// the item. Returns whether the key exists.
+func (m *Memory) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	if m == nil {
+		return false, nil
+	}
+	// ensure we don't get a collision if two or more goroutines try to write concurrently
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return false, ErrCacheClosed
+	}
+	now := time.Now()
+	item := m.getLiveItem(key, now)
+	if item == nil {
+		return false, nil
+	}
+	if ttl <= 0 {
+		m.removeItem(item)
+		return true, nil
+	}
+	m.setItemExpiry(item, now.Add(ttl))
+	return true, nil
+}
+
+// Get retrieves the item associated with the given key from the cache. If the key could not be found, this method
+// returns nil.
+func (m *Memory) Get(ctx context.Context, key string) ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	// ensure we don't get a collision if two or more goroutines try to read concurrently
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil, ErrCacheClosed
+	}
+	item := m.getLiveItem(key, time.Now())
+	if item == nil {
+		return nil, nil
+	}
+	m.touchItem(item)
+	return copyBytes(item.value), nil
+}
+
+// GetAndDelete atomically retrieves and removes the item associated with the given key from the cache. If the key
+// could not be found, this method returns nil.
+func (m *Memory) GetAndDelete(ctx context.Context, key string) ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	// ensure we don't get a collision if two or more goroutines try to write concurrently
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil, ErrCacheClosed
+	}
+	item := m.getLiveItem(key, time.Now())
+	if item == nil {
+		return nil, nil
+	}
+	m.removeItem(item)
+	return item.value, nil
+}
+
+// GetMany retrieves the items associated with the given keys from the cache. Keys that could not be found are
+// omitted from the returned map.
+func (m *Memory) GetMany(ctx context.Context, keys ...string) (map[string][]byte, error) {
+	values := map[string][]byte{}
+	if m == nil {
+		return values, nil
+	}
+	// ensure we don't get a collision if two or more goroutines try to read concurrently
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil, ErrCacheClosed
+	}
+	now := time.Now()
+	for _, key := range keys {
+		if item := m.getLiveItem(key, now); item != nil {
+			m.touchItem(item)
+			values[key] = copyBytes(item.value)
+		}
+	}
+	return values, nil
+}
+
+// Increment atomically increments the integer value associated with the given key by the delta and returns the new
+// value. A missing key is treated as zero.
+func (m *Memory) Increment(ctx context.Context, key string, delta int64) (int64, error) {
+	return m.IncrementWithTTL(ctx, key, delta, 0)
+}
+
+// IncrementWithTTL performs the same operation as Increment but also applies the time-to-live (TTL) duration if the
+// key does not already expire.
+func (m *Memory) IncrementWithTTL(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	if m == nil {
+		return 0, nil
+	}
+	// ensure we don't get a collision if two or more goroutines try to write concurrently
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return 0, ErrCacheClosed
+	}
+	now := time.Now()
+	var current int64
+	expiresAt := time.Time{}
+	if item := m.getLiveItem(key, now); item != nil {
+		parsed, err := strconv.ParseInt(string(item.value), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s", ErrValueNotInteger, key)
+		}
+		current = parsed
+		expiresAt = item.expiresAt
+	}
+	next := current + delta
+	if (delta > 0 && next < current) || (delta < 0 && next > current) {
+		return 0, fmt.Errorf("%w: %s", ErrValueOutOfRange, key)
+	}
+	if expiresAt.IsZero() {
+		expiresAt = getExpiryFromTTL(now, ttl)
+	}
+	m.setItem(key, []byte(strconv.FormatInt(next, 10)), expiresAt, now)
+	return next, nil
+}
+
+// Len returns the number of items currently held by the cache, including expired items that have not been removed
+// yet.
+func (m *Memory) Len() int {
+	if m == nil {
+		return 0
+	}
+	// ensure we don't get a collision if two or more goroutines try to read concurrently
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.items)
+}
+
+// Scan returns a page of the keys that match the glob pattern, examining roughly count keys, along with the cursor
+// from which to continue. Keys are visited in the order of their hashes and the cursor is the hash to continue from,
+// so keys that exist for the whole iteration are returned exactly once. Each call visits every item but only orders
+// the hashes of the page itself, so it takes time proportional to the number of items held times the logarithm of
+// count.
+func (m *Memory) Scan(ctx context.Context, cursor uint64, pattern string, count int64) ([]string, uint64, error) {
+	if m == nil {
+		return nil, 0, nil
+	}
+	if count <= 0 {
+		count = DefaultScanCount
+	}
+	// ensure we don't get a collision if two or more goroutines try to read concurrently
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil, 0, ErrCacheClosed
+	}
+	now := time.Now()
+	// the largest of the count smallest hashes at or above the cursor ends the page
+	smallest := &scanHashHeap{}
+	for _, item := range m.items {
+		if item.hash < cursor || item.isExpired(now) {
+			continue
+		}
+		if smallest.Len() < int(count) {
+			heap.Push(smallest, item.hash)
+		} else if item.hash < (*smallest)[0] {
+			(*smallest)[0] = item.hash
+			heap.Fix(smallest, 0)
+		}
+	}
+	if smallest.Len() == 0 {
+		return []string{}, 0, nil
+	}
+	// keys sharing a hash are never split across pages, as the cursor could not tell them apart
+	last := (*smallest)[0]
+	page := make([]*memoryItem, 0, smallest.Len())
+	hasMore := false
+	for _, item := range m.items {
+		if item.hash < cursor || item.isExpired(now) {
+			continue
+		}
+		if item.hash > last {
+			hasMore = true
+		} else {
+			page = append(page, item)
+		}
+	}
+	slices.SortFunc(page, func(a, b *memoryItem) int {
+		return cmp.Compare(a.hash, b.hash)
+	})
+	keys := []string{}
+	for _, item := range page {
+		if MatchPattern(pattern, item.key) {
+			keys = append(keys, item.key)
+		}
+	}
+	if !hasMore || last == math.MaxUint64 {
+		return keys, 0, nil
+	}
+	return keys, last + 1, nil
+}
+
+// Set stores the given value associated with the given key in the cache.
+func (m *Memory) Set(ctx context.Context, key string, value []byte) error {
+	if m == nil {
+		return nil
+	}
+	return m.SetWithTTL(ctx, key, value, 0)
+}
+
+// SetIfNotExists stores the given value associated with the given key in the cache along with a time-to-live (TTL)
+// duration, but only if the key does not already exist. A zero TTL means the item never expires. Returns whether the
+// value was stored.
+func (m *Memory) SetIfNotExists(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	if m == nil {
+		return false, nil
+	}
+	// ensure we don't get a collision if two or more goroutines try to write concurrently
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return false, ErrCacheClosed
+	}
+	now := time.Now()
+	if m.getLiveItem(key, now) != nil {
+		return false, nil
+	}
+	m.setItem(key, value, getExpiryFromTTL(now, ttl), now)
+	return true, nil
+}
+
+// SetMany stores each of the given values associated with its key in the cache along with a time-to-live (TTL)
+// duration. A zero TTL means the items never expire.
+func (m *Memory) SetMany(ctx context.Context, values map[string][]byte, ttl time.Duration) error {
+	if m == nil {
+		return nil
+	}
+	// ensure we don't get a collision if two or more goroutines try to write concurrently
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return ErrCacheClosed
+	}
+	now := time.Now()
+	expiresAt := getExpiryFromTTL(now, ttl)
+	for key, value := range values {
+		m.setItem(key, value, expiresAt, now)
+	}
+	return nil
+}
+
+// SetWithTTL stores the given value associated with the given key in the cache along with a time-to-live (TTL)
+// duration. A zero TTL means the item never expires.
+func (m *Memory) SetWithTTL(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if m == nil {
+		return nil
+	}
+	// ensure we don't get a collision if two or more goroutines try to write concurrently
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return ErrCacheClosed
+	}
+	now := time.Now()
+	m.setItem(key, value, getExpiryFromTTL(now, ttl), now)
+	return nil
+}
+
+// TTL returns the remaining time-to-live (TTL) duration of the item associated with the given key. Returns
+// TTLNoExpiry if the item never expires and TTLKeyNotFound if the key does not exist.
+func (m *Memory) TTL(ctx context.Context, key string) (time.Duration, error) {
+	if m == nil {
+		return TTLKeyNotFound, nil
+	}
+	// ensure we don't get a collision if two or more goroutines try to read concurrently
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return TTLKeyNotFound, ErrCacheClosed
+	}
+	now := time.Now()
+	item := m.getLiveItem(key, now)
+	if item == nil {
+		return TTLKeyNotFound, nil
+	}
+	if item.expiresAt.IsZero() {
+		return TTLNoExpiry, nil
+	}
+	return item.expiresAt.Sub(now), nil
+}
+
+// cleanup removes every expired item from the cache at the given interval until the cache is closed.
+func (m *Memory) cleanup(interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			m.mu.Lock()
+			m.removeExpiredItems(now)
+			m.mu.Unlock()
+		}
+	}
+}
+
+// getLiveItem returns the item associated with the key, or nil if it does not exist. Expired items are removed and
+// treated as missing. The caller must hold the lock.
+func (m *Memory) getLiveItem(key string, now time.Time) *memoryItem {
+	item, exists := m.items[key]
+	if !exists {
+		return nil
+	}
+	if item.isExpired(now) {
+		m.removeItem(item)
+		return nil
+	}
+	return item
+}
+
+// makeRoom removes items until there is space for one more item. Expired items are removed first, followed by the
+// items chosen by the eviction policy. Both are taken from the top of their heaps, so only the removed items are
+// visited. The caller must hold the lock.
+func (m *Memory) makeRoom(now time.Time) {
+	if m.maxEntries <= 0 || len(m.items) < m.maxEntries {
+		return
+	}
+	m.removeExpiredItems(now)
+	for len(m.items) >= m.maxEntries && m.evictions.Len() > 0 {
+		m.removeItem(m.evictions.items[0])
+	}
+}
+
+// removeExpiredItems removes every item that has expired as of the given time. The caller must hold the lock.
+func (m *Memory) removeExpiredItems(now time.Time) {
+	for m.expiries.Len() > 0 && m.expiries.items[0].isExpired(now) {
+		m.removeItem(m.expiries.items[0])
+	}
+}
+
+// removeItem removes the item from the cache. The caller must hold the lock.
+func (m *Memory) removeItem(item *memoryItem) {
+	delete(m.items, item.key)
+	if item.index >= 0 {
+		heap.Remove(m.evictions, item.index)
+	}
+	if item.expiryIndex >= 0 {
+		heap.Remove(m.expiries, item.expiryIndex)
+	}
+}
+
+// setItem stores a copy of the value under the key with the given expiry, making room for it first if it is a new
+// item. The caller must hold the lock.
+func (m *Memory) setItem(key string, value []byte, expiresAt time.Time, now time.Time) {
+	if item := m.getLiveItem(key, now); item != nil {
+		item.value = copyBytes(value)
+		m.setItemExpiry(item, expiresAt)
+		m.touchItem(item)
+		return
+	}
+	m.makeRoom(now)
+	item := &memoryItem{
+		expiryIndex: -1,
+		hash:        hashKey(key),
+		key:         key,
+		value:       copyBytes(value),
+	}
+	m.items[key] = item
+	heap.Push(m.evictions, item)
+	m.setItemExpiry(item, expiresAt)
+	m.touchItem(item)
+}
+
+// setItemExpiry changes the time at which the item expires and keeps the expiry heap in order. The caller must hold
+// the lock.
+func (m *Memory) setItemExpiry(item *memoryItem, expiresAt time.Time) {
+	item.expiresAt = expiresAt
+	switch {
+	case expiresAt.IsZero():
+		if item.expiryIndex >= 0 {
+			heap.Remove(m.expiries, item.expiryIndex)
+		}
+	case item.expiryIndex >= 0:
+		heap.Fix(m.expiries, item.expiryIndex)
+	default:
+		heap.Push(m.expiries, item)
+	}
+}
+
+// touchItem records a use of the item for the eviction policy. The caller must hold the lock.
+func (m *Memory) touchItem(item *memoryItem) {
+	m.clock++
+	item.frequency++
+	item.lastUsed = m.clock
+	heap.Fix(m.evictions, item.index)
+}
+
+// NewMemory creates and returns a new in-memory cache instance along with any error that may have occurred.
+//
+// The cache starts a background goroutine that removes expired items unless cleanup has been turned off, so Close()
+// should be called once the cache is no longer needed.
+func NewMemory(connectionArguments *MemoryConnectionArguments) (*Memory, error) {
+	err := ValidateMemoryConnectionArguments(connectionArguments)
+	if err != nil {
+		return nil, err
+	}
+	policy := connectionArguments.EvictionPolicy
+	if policy == "" {
+		policy = EvictionPolicyLRU
+	}
+	memory := &Memory{
+		evictions: &memoryEvictionHeap{
+			items:  []*memoryItem{},
+			policy: policy,
+		},
+		expiries: &memoryExpiryHeap{
+			items: []*memoryItem{},
+		},
+		items:      map[string]*memoryItem{},
+		maxEntries: connectionArguments.MaxEntries,
+	}
+	cleanupInterval := connectionArguments.CleanupInterval
+	if cleanupInterval == 0 {
+		cleanupInterval = DefaultMemoryCleanupInterval
+	}
+	if cleanupInterval > 0 {
+		memory.stop = make(chan struct{})
+		go memory.cleanup(cleanupInterval, memory.stop)
+	}
+	return memory, nil
+}
+
+// ValidateMemoryConnectionArguments takes a MemoryConnectionArguments struct pointer and returns an error if any of
+// the fields are invalid. Returns nil if the validation checks pass.
+func ValidateMemoryConnectionArguments(connectionArguments *MemoryConnectionArguments) error {
+	if connectionArguments == nil {
+		return ErrMemoryNoConnectionArguments
+	}
+	if connectionArguments.MaxEntries < 0 {
+		return ErrMemoryInvalidMaxEntries
+	}
+	switch connectionArguments.EvictionPolicy {
+	case "", EvictionPolicyLFU, EvictionPolicyLRU:
+		return nil
+	}
+	return ErrInvalidEvictionPolicy
+}
+
+// getExpiryFromTTL returns the time at which an item stored now with the TTL expires, or the zero time if the TTL is
+// not positive and the item therefore never expires.
+func getExpiryFromTTL(now time.Time, ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return now.Add(ttl)
+}
+
+// copyBytes returns a copy of the byte slice so that callers cannot modify the values held by the cache.
+func copyBytes(value []byte) []byte {
+	if value == nil {
+		return []byte{}
+	}
+	return append([]byte{}, value...)
+}
+
+// hashKey returns the hash that determines the position of the key within a scan of the in-memory cache.
+func hashKey(key string) uint64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(key))
+	return hash.Sum64()
+}
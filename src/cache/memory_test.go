@@ -0,0 +1,307 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// newTestMemory returns a new in-memory cache with the connection arguments that is closed once the test finishes.
+func newTestMemory(t *testing.T, connectionArguments *MemoryConnectionArguments) *Memory {
+	t.Helper()
+	memory, err := NewMemory(connectionArguments)
+	if err != nil {
+		t.Fatalf("NewMemory() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = memory.Close()
+	})
+	return memory
+}
+
+func TestMemoryReturnsNilOnMiss(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(ctx context.Context, m *Memory)
+	}{
+		{"never stored", func(ctx context.Context, m *Memory) {}},
+		{"deleted", func(ctx context.Context, m *Memory) {
+			_ = m.Set(ctx, "key", []byte("value"))
+			_, _ = m.Delete(ctx, "key")
+		}},
+		{"taken", func(ctx context.Context, m *Memory) {
+			_ = m.Set(ctx, "key", []byte("value"))
+			_, _ = m.GetAndDelete(ctx, "key")
+		}},
+		{"expired", func(ctx context.Context, m *Memory) {
+			_ = m.SetWithTTL(ctx, "key", []byte("value"), time.Millisecond)
+			time.Sleep(5 * time.Millisecond)
+		}},
+		{"removed by a non-positive expiry", func(ctx context.Context, m *Memory) {
+			_ = m.Set(ctx, "key", []byte("value"))
+			_, _ = m.Expire(ctx, "key", 0)
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			memory := newTestMemory(t, &MemoryConnectionArguments{CleanupInterval: -1})
+			test.setup(ctx, memory)
+			if value, err := memory.Get(ctx, "key"); value != nil || err != nil {
+				t.Errorf("Get() = (%q, %v), want (nil, nil)", value, err)
+			}
+			if value, err := memory.GetAndDelete(ctx, "key"); value != nil || err != nil {
+				t.Errorf("GetAndDelete() = (%q, %v), want (nil, nil)", value, err)
+			}
+			if values, err := memory.GetMany(ctx, "key"); len(values) != 0 || err != nil {
+				t.Errorf("GetMany() = (%v, %v), want an empty map", values, err)
+			}
+			if exists, err := memory.Exists(ctx, "key"); exists || err != nil {
+				t.Errorf("Exists() = (%v, %v), want (false, nil)", exists, err)
+			}
+			if ttl, err := memory.TTL(ctx, "key"); ttl != TTLKeyNotFound || err != nil {
+				t.Errorf("TTL() = (%v, %v), want (%v, nil)", ttl, err, TTLKeyNotFound)
+			}
+		})
+	}
+}
+
+func TestMemoryTTLExpiry(t *testing.T) {
+	tests := []struct {
+		name      string
+		store     func(ctx context.Context, m *Memory) error
+		wait      time.Duration
+		wantValue []byte
+		wantTTL   func(ttl time.Duration) bool
+	}{
+		{
+			name: "without ttl never expires",
+			store: func(ctx context.Context, m *Memory) error {
+				return m.Set(ctx, "key", []byte("value"))
+			},
+			wait:      20 * time.Millisecond,
+			wantValue: []byte("value"),
+			wantTTL:   func(ttl time.Duration) bool { return ttl == TTLNoExpiry },
+		},
+		{
+			name: "ttl not yet reached",
+			store: func(ctx context.Context, m *Memory) error {
+				return m.SetWithTTL(ctx, "key", []byte("value"), time.Hour)
+			},
+			wantValue: []byte("value"),
+			wantTTL:   func(ttl time.Duration) bool { return ttl > 59*time.Minute && ttl <= time.Hour },
+		},
+		{
+			name: "ttl reached",
+			store: func(ctx context.Context, m *Memory) error {
+				return m.SetWithTTL(ctx, "key", []byte("value"), 10*time.Millisecond)
+			},
+			wait:    20 * time.Millisecond,
+			wantTTL: func(ttl time.Duration) bool { return ttl == TTLKeyNotFound },
+		},
+		{
+			name: "overwrite without ttl removes the expiry",
+			store: func(ctx context.Context, m *Memory) error {
+				if err := m.SetWithTTL(ctx, "key", []byte("old"), 10*time.Millisecond); err != nil {
+					return err
+				}
+				return m.Set(ctx, "key", []byte("value"))
+			},
+			wait:      20 * time.Millisecond,
+			wantValue: []byte("value"),
+			wantTTL:   func(ttl time.Duration) bool { return ttl == TTLNoExpiry },
+		},
+		{
+			name: "expire shortens the ttl",
+			store: func(ctx context.Context, m *Memory) error {
+				if err := m.SetWithTTL(ctx, "key", []byte("value"), time.Hour); err != nil {
+					return err
+				}
+				_, err := m.Expire(ctx, "key", 10*time.Millisecond)
+				return err
+			},
+			wait:    20 * time.Millisecond,
+			wantTTL: func(ttl time.Duration) bool { return ttl == TTLKeyNotFound },
+		},
+		{
+			name: "increment keeps the existing expiry",
+			store: func(ctx context.Context, m *Memory) error {
+				if _, err := m.IncrementWithTTL(ctx, "key", 1, 10*time.Millisecond); err != nil {
+					return err
+				}
+				_, err := m.IncrementWithTTL(ctx, "key", 1, time.Hour)
+				return err
+			},
+			wait:    20 * time.Millisecond,
+			wantTTL: func(ttl time.Duration) bool { return ttl == TTLKeyNotFound },
+		},
+		{
+			name: "increment applies the ttl to a key without expiry",
+			store: func(ctx context.Context, m *Memory) error {
+				if _, err := m.Increment(ctx, "key", 1); err != nil {
+					return err
+				}
+				_, err := m.IncrementWithTTL(ctx, "key", 1, time.Hour)
+				return err
+			},
+			wantValue: []byte("2"),
+			wantTTL:   func(ttl time.Duration) bool { return ttl > 59*time.Minute && ttl <= time.Hour },
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			memory := newTestMemory(t, &MemoryConnectionArguments{CleanupInterval: -1})
+			if err := test.store(ctx, memory); err != nil {
+				t.Fatalf("store error = %v", err)
+			}
+			time.Sleep(test.wait)
+			value, err := memory.Get(ctx, "key")
+			if err != nil || string(value) != string(test.wantValue) || (value == nil) != (test.wantValue == nil) {
+				t.Errorf("Get() = (%q, %v), want %q", value, err, test.wantValue)
+			}
+			if ttl, err := memory.TTL(ctx, "key"); err != nil || !test.wantTTL(ttl) {
+				t.Errorf("TTL() = (%v, %v), unexpected", ttl, err)
+			}
+		})
+	}
+}
+
+func TestMemoryCleanupRemovesExpiredItems(t *testing.T) {
+	ctx := context.Background()
+	memory := newTestMemory(t, &MemoryConnectionArguments{CleanupInterval: 5 * time.Millisecond})
+	_ = memory.SetWithTTL(ctx, "expiring", []byte("value"), time.Millisecond)
+	_ = memory.Set(ctx, "kept", []byte("value"))
+	deadline := time.Now().Add(time.Second)
+	for memory.Len() > 1 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := memory.Len(); got != 1 {
+		t.Errorf("Len() = %d, want 1 once the expired item has been cleaned up", got)
+	}
+}
+
+func TestMemoryEvictionOrder(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      EvictionPolicy
+		uses        []string
+		wantEvicted string
+	}{
+		{"lru evicts the oldest item", EvictionPolicyLRU, nil, "a"},
+		{"lru keeps recently read items", EvictionPolicyLRU, []string{"a"}, "b"},
+		{"lru keeps recently read items in order", EvictionPolicyLRU, []string{"b", "a"}, "c"},
+		{"default policy is lru", "", []string{"a", "b"}, "c"},
+		{"lfu breaks ties by recency", EvictionPolicyLFU, nil, "a"},
+		{"lfu evicts the least frequently used item", EvictionPolicyLFU, []string{"a", "a", "b", "c"}, "b"},
+		{"lfu keeps frequent items that are old", EvictionPolicyLFU, []string{"a", "a", "c", "b"}, "c"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			memory := newTestMemory(t, &MemoryConnectionArguments{
+				CleanupInterval: -1,
+				EvictionPolicy:  test.policy,
+				MaxEntries:      3,
+			})
+			for _, key := range []string{"a", "b", "c"} {
+				_ = memory.Set(ctx, key, []byte(key))
+			}
+			for _, key := range test.uses {
+				_, _ = memory.Get(ctx, key)
+			}
+			_ = memory.Set(ctx, "d", []byte("d"))
+			if got := memory.Len(); got != 3 {
+				t.Errorf("Len() = %d, want 3", got)
+			}
+			for _, key := range []string{"a", "b", "c", "d"} {
+				exists, _ := memory.Exists(ctx, key)
+				if exists == (key == test.wantEvicted) {
+					t.Errorf("Exists(%q) = %v, want %q to be the only evicted key", key, exists, test.wantEvicted)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryEvictsExpiredItemsFirst(t *testing.T) {
+	ctx := context.Background()
+	memory := newTestMemory(t, &MemoryConnectionArguments{CleanupInterval: -1, MaxEntries: 2})
+	_ = memory.Set(ctx, "oldest", []byte("value"))
+	_ = memory.SetWithTTL(ctx, "expiring", []byte("value"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	_ = memory.Set(ctx, "newest", []byte("value"))
+	if exists, _ := memory.Exists(ctx, "oldest"); !exists {
+		t.Error("Exists(\"oldest\") = false, want the expired item to be removed instead")
+	}
+}
+
+func TestMemoryScanCursorStability(t *testing.T) {
+	tests := []struct {
+		name    string
+		count   int64
+		pattern string
+		want    func(key string) bool
+	}{
+		{"one key per page", 1, "*", func(key string) bool { return true }},
+		{"small pages", 7, "*", func(key string) bool { return true }},
+		{"single page", 1000, "*", func(key string) bool { return true }},
+		{"default count", 0, "", func(key string) bool { return true }},
+		{"pattern", 5, "user:1*", func(key string) bool { return MatchPattern("user:1*", key) }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			memory := newTestMemory(t, &MemoryConnectionArguments{CleanupInterval: -1})
+			stable := map[string]bool{}
+			for i := range 100 {
+				key := "user:" + strconv.Itoa(i)
+				_ = memory.Set(ctx, key, []byte("value"))
+				stable[key] = true
+			}
+			// keys removed or added during the iteration may or may not be returned, but never more than once
+			for i := range 10 {
+				delete(stable, "user:"+strconv.Itoa(i*10))
+			}
+			seen := map[string]int{}
+			cursor, pages := uint64(0), 0
+			for {
+				keys, nextCursor, err := memory.Scan(ctx, cursor, test.pattern, test.count)
+				if err != nil {
+					t.Fatalf("Scan() error = %v", err)
+				}
+				for _, key := range keys {
+					seen[key]++
+				}
+				if pages == 1 {
+					for i := range 10 {
+						_, _ = memory.Delete(ctx, "user:"+strconv.Itoa(i*10))
+						_ = memory.Set(ctx, "new:"+strconv.Itoa(i), []byte("value"))
+					}
+				}
+				pages++
+				if nextCursor == 0 {
+					break
+				}
+				if nextCursor <= cursor {
+					t.Fatalf("Scan() cursor went from %d to %d", cursor, nextCursor)
+				}
+				cursor = nextCursor
+			}
+			for key, times := range seen {
+				if times > 1 {
+					t.Errorf("Scan() returned %q %d times", key, times)
+				}
+				if !test.want(key) {
+					t.Errorf("Scan() returned %q, which does not match %q", key, test.pattern)
+				}
+			}
+			for key := range stable {
+				if test.want(key) && seen[key] != 1 {
+					t.Errorf("Scan() returned %q %d times, want once", key, seen[key])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,507 @@
+package cache
+
+import (
+	"context"
+	"encoding/binary"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// taggedEnvelopeVersion is the version of the envelope layout written for tagged values.
+const taggedEnvelopeVersion byte = 1
+
+// untaggedEnvelopeVersion marks the envelope written for untagged values whose own bytes start with the envelope magic.
+const untaggedEnvelopeVersion byte = 0
+
+// permanentTagTTL is the time-to-live (TTL) duration given to a tag that already expires once a value that never
+// expires is stored under it, since the wrapped cache cannot remove an expiry.
+const permanentTagTTL time.Duration = 100 * 365 * 24 * time.Hour
+
+// envelopeMagic identifies values that are wrapped in an envelope, either because they were stored with tags or
+// because they would otherwise be mistaken for one.
+var envelopeMagic = [2]byte{0xC5, 0x54}
+
+// Namespaced represents a caching mechanism that prefixes every key with a namespace (e.g., the service name) and a
+// version before passing it on to the wrapped cache, so that services sharing a cache cannot collide and bumping the
+// version abandons every existing key at once.
+//
+// Values can also be stored under tags with SetWithTags. Invalidating a tag makes every value stored under it a miss.
+// Each tag has a version stored within the cache under a reserved prefix that no key within the namespace can produce
+// (e.g., "billing:v2\x00tags:user"); tagged values record the versions of their tags when they are stored and are
+// discarded when read if any of those versions has changed since. A tag version lives at least as long as the longest
+// lived value stored under it, and invalidating a tag removes its version. Untagged values are stored
+// unchanged (so integer values still work with Increment), except for those that start with the same bytes as a tagged
+// value, which are wrapped in an untagged envelope so that they are never mistaken for one.
+type Namespaced struct {
+	implementation Contract
+	prefix         string
+	tagPrefix      string
+}
+
+// Close closes the wrapped cache.
+func (n *Namespaced) Close() error {
+	if n == nil || n.implementation == nil {
+		return nil
+	}
+	return n.implementation.Close()
+}
+
+// Decrement atomically decrements the integer value associated with the given key by the delta and returns the new
+// value. A missing key is treated as zero.
+func (n *Namespaced) Decrement(ctx context.Context, key string, delta int64) (int64, error) {
+	if n == nil || n.implementation == nil {
+		return 0, nil
+	}
+	return n.implementation.Decrement(ctx, n.prefix+key, delta)
+}
+
+// DecrementWithTTL performs the same operation as Decrement but also applies the time-to-live (TTL) duration if the
+// key does not already expire.
+func (n *Namespaced) DecrementWithTTL(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	if n == nil || n.implementation == nil {
+		return 0, nil
+	}
+	return n.implementation.DecrementWithTTL(ctx, n.prefix+key, delta, ttl)
+}
+
+// Delete destroys the item associated with the given key from the cache. The integer return value indicates the number
+// of items that were deleted.
+func (n *Namespaced) Delete(ctx context.Context, key string) (int64, error) {
+	if n == nil || n.implementation == nil {
+		return 0, nil
+	}
+	return n.implementation.Delete(ctx, n.prefix+key)
+}
+
+// DeleteMany destroys the items associated with the given keys from the cache. The integer return value indicates
+// the number of items that were deleted.
+func (n *Namespaced) DeleteMany(ctx context.Context, keys ...string) (int64, error) {
+	if n == nil || n.implementation == nil {
+		return 0, nil
+	}
+	return n.implementation.DeleteMany(ctx, n.prefixKeys(keys)...)
+}
+
+// Exists checks if an item with the given key exists in the cache. A tagged value counts as existing until it is read
+// after one of its tags has been invalidated.
+func (n *Namespaced) Exists(ctx context.Context, key string) (bool, error) {
+	if n == nil || n.implementation == nil {
+		return false, nil
+	}
+	return n.implementation.Exists(ctx, n.prefix+key)
+}
+
+// Expire sets the time-to-live (TTL) duration of the item associated with the given key. A non-positive TTL removes
+// the item. Returns whether the key exists.
+func (n *Namespaced) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	if n == nil || n.implementation == nil {
+		return false, nil
+	}
+	return n.implementation.Expire(ctx, n.prefix+key, ttl)
+}
+
+// Get retrieves the item associated with the given key from the cache. If the key could not be found, or it was
+// stored under a tag that has since been invalidated, this method returns nil.
+func (n *Namespaced) Get(ctx context.Context, key string) ([]byte, error) {
+	if n == nil || n.implementation == nil {
+		return nil, nil
+	}
+	values, err := n.GetMany(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return values[key], nil
+}
+
+// GetAndDelete atomically retrieves and removes the item associated with the given key from the cache. If the key
+// could not be found, or it was stored under a tag that has since been invalidated, this method returns nil.
+func (n *Namespaced) GetAndDelete(ctx context.Context, key string) ([]byte, error) {
+	if n == nil || n.implementation == nil {
+		return nil, nil
+	}
+	value, err := n.implementation.GetAndDelete(ctx, n.prefix+key)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	values, _, err := n.unwrapTagged(ctx, map[string][]byte{key: value})
+	if err != nil {
+		return nil, err
+	}
+	return values[key], nil
+}
+
+// GetMany retrieves the items associated with the given keys from the cache. Keys that could not be found, or that
+// were stored under a tag that has since been invalidated, are omitted from the returned map.
+func (n *Namespaced) GetMany(ctx context.Context, keys ...string) (map[string][]byte, error) {
+	if n == nil || n.implementation == nil {
+		return map[string][]byte{}, nil
+	}
+	items, err := n.implementation.GetMany(ctx, n.prefixKeys(keys)...)
+	if err != nil {
+		return map[string][]byte{}, err
+	}
+	values := make(map[string][]byte, len(items))
+	for key, value := range items {
+		values[strings.TrimPrefix(key, n.prefix)] = value
+	}
+	values, stale, err := n.unwrapTagged(ctx, values)
+	if err != nil {
+		return map[string][]byte{}, err
+	}
+	if len(stale) > 0 {
+		_, _ = n.implementation.DeleteMany(ctx, n.prefixKeys(stale)...)
+	}
+	return values, nil
+}
+
+// Implementation returns the cache wrapped by this namespaced cache.
+func (n *Namespaced) Implementation() Contract {
+	if n == nil {
+		return nil
+	}
+	return n.implementation
+}
+
+// Increment atomically increments the integer value associated with the given key by the delta and returns the new
+// value. A missing key is treated as zero.
+func (n *Namespaced) Increment(ctx context.Context, key string, delta int64) (int64, error) {
+	if n == nil || n.implementation == nil {
+		return 0, nil
+	}
+	return n.implementation.Increment(ctx, n.prefix+key, delta)
+}
+
+// IncrementWithTTL performs the same operation as Increment but also applies the time-to-live (TTL) duration if the
+// key does not already expire.
+func (n *Namespaced) IncrementWithTTL(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	if n == nil || n.implementation == nil {
+		return 0, nil
+	}
+	return n.implementation.IncrementWithTTL(ctx, n.prefix+key, delta, ttl)
+}
+
+// InvalidateTags makes every value stored under any of the given tags a miss by removing the versions of the tags. The
+// values themselves are removed the next time they are read or once they expire.
+func (n *Namespaced) InvalidateTags(ctx context.Context, tags ...string) error {
+	if n == nil || n.implementation == nil || len(tags) == 0 {
+		return nil
+	}
+	tagKeys := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tagKeys = append(tagKeys, n.tagKey(tag))
+	}
+	_, err := n.implementation.DeleteMany(ctx, tagKeys...)
+	return err
+}
+
+// Prefix returns the prefix added to every key (e.g., "billing:v2:").
+func (n *Namespaced) Prefix() string {
+	if n == nil {
+		return ""
+	}
+	return n.prefix
+}
+
+// Scan returns a page of the keys within the namespace that match the glob pattern, examining roughly count keys,
+// along with the cursor from which to continue. The returned keys do not include the prefix. The tag versions are kept
+// outside of the namespace and are never returned.
+func (n *Namespaced) Scan(ctx context.Context, cursor uint64, pattern string, count int64) ([]string, uint64, error) {
+	if n == nil || n.implementation == nil {
+		return nil, 0, nil
+	}
+	if pattern == "" {
+		pattern = "*"
+	}
+	keys, nextCursor, err := n.implementation.Scan(ctx, cursor, EscapePattern(n.prefix)+pattern, count)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i, key := range keys {
+		keys[i] = strings.TrimPrefix(key, n.prefix)
+	}
+	return keys, nextCursor, nil
+}
+
+// Set stores the given value associated with the given key in the cache.
+func (n *Namespaced) Set(ctx context.Context, key string, value []byte) error {
+	if n == nil || n.implementation == nil {
+		return nil
+	}
+	return n.implementation.Set(ctx, n.prefix+key, encodeUntaggedValue(value))
+}
+
+// SetIfNotExists stores the given value associated with the given key in the cache along with a time-to-live (TTL)
+// duration, but only if the key does not already exist. A zero TTL means the item never expires. Returns whether the
+// value was stored.
+func (n *Namespaced) SetIfNotExists(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	if n == nil || n.implementation == nil {
+		return false, nil
+	}
+	return n.implementation.SetIfNotExists(ctx, n.prefix+key, encodeUntaggedValue(value), ttl)
+}
+
+// SetMany stores each of the given values associated with its key in the cache along with a time-to-live (TTL)
+// duration. A zero TTL means the items never expire.
+func (n *Namespaced) SetMany(ctx context.Context, values map[string][]byte, ttl time.Duration) error {
+	if n == nil || n.implementation == nil {
+		return nil
+	}
+	prefixed := make(map[string][]byte, len(values))
+	for key, value := range values {
+		prefixed[n.prefix+key] = encodeUntaggedValue(value)
+	}
+	return n.implementation.SetMany(ctx, prefixed, ttl)
+}
+
+// SetWithTags stores the given value associated with the given key in the cache along with a time-to-live (TTL)
+// duration, under each of the given tags. A zero TTL means the item never expires. The version of each tag is kept for
+// at least as long as the value. Tagged values should not be incremented or decremented.
+func (n *Namespaced) SetWithTags(
+	ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string,
+) error {
+	if n == nil || n.implementation == nil {
+		return nil
+	}
+	if len(tags) == 0 {
+		return n.SetWithTTL(ctx, key, value, ttl)
+	}
+	versions, err := n.getTagVersions(ctx, tags, true, ttl)
+	if err != nil {
+		return err
+	}
+	if err = n.extendTagTTLs(ctx, tags, ttl); err != nil {
+		return err
+	}
+	return n.implementation.SetWithTTL(ctx, n.prefix+key, encodeTaggedEnvelope(tags, versions, value), ttl)
+}
+
+// SetWithTTL stores the given value associated with the given key in the cache along with a time-to-live (TTL)
+// duration.
+func (n *Namespaced) SetWithTTL(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if n == nil || n.implementation == nil {
+		return nil
+	}
+	return n.implementation.SetWithTTL(ctx, n.prefix+key, encodeUntaggedValue(value), ttl)
+}
+
+// TTL returns the remaining time-to-live (TTL) duration of the item associated with the given key. Returns
+// TTLNoExpiry if the item never expires and TTLKeyNotFound if the key does not exist.
+func (n *Namespaced) TTL(ctx context.Context, key string) (time.Duration, error) {
+	if n == nil || n.implementation == nil {
+		return TTLKeyNotFound, nil
+	}
+	return n.implementation.TTL(ctx, n.prefix+key)
+}
+
+// extendTagTTLs extends the time-to-live (TTL) duration of each of the given tags so that it lasts at least as long as
+// a value stored with the TTL. A zero TTL means the value never expires. Returns any error that may have occurred.
+func (n *Namespaced) extendTagTTLs(ctx context.Context, tags []string, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = permanentTagTTL
+	}
+	for _, tag := range tags {
+		remaining, err := n.implementation.TTL(ctx, n.tagKey(tag))
+		if err != nil {
+			return err
+		}
+		// tags that never expire or that have since been invalidated are left alone
+		if remaining < 0 || remaining >= ttl {
+			continue
+		}
+		if _, err = n.implementation.Expire(ctx, n.tagKey(tag), ttl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getTagVersions returns the current version of each of the given tags. A tag that has no version yet has version
+// zero unless it should be initialized with the TTL, in which case it starts at the current time in nanoseconds;
+// starting from a unique value ensures that a tag whose version has been removed or evicted cannot make old values
+// valid again.
+func (n *Namespaced) getTagVersions(
+	ctx context.Context, tags []string, initialize bool, ttl time.Duration,
+) (map[string]int64, error) {
+	tagKeys := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tagKeys = append(tagKeys, n.tagKey(tag))
+	}
+	items, err := n.implementation.GetMany(ctx, tagKeys...)
+	if err != nil {
+		return nil, err
+	}
+	versions := make(map[string]int64, len(tags))
+	for _, tag := range tags {
+		value, exists := items[n.tagKey(tag)]
+		if !exists && initialize {
+			version := strconv.FormatInt(time.Now().UnixNano(), 10)
+			stored, err := n.implementation.SetIfNotExists(ctx, n.tagKey(tag), []byte(version), ttl)
+			if err != nil {
+				return nil, err
+			}
+			if !stored {
+				// another writer initialized the tag first
+				if value, err = n.implementation.Get(ctx, n.tagKey(tag)); err != nil {
+					return nil, err
+				}
+			} else {
+				value = []byte(version)
+			}
+		}
+		versions[tag], _ = strconv.ParseInt(string(value), 10, 64)
+	}
+	return versions, nil
+}
+
+// prefixKeys returns a copy of the keys with the prefix added to each of them.
+func (n *Namespaced) prefixKeys(keys []string) []string {
+	prefixed := make([]string, 0, len(keys))
+	for _, key := range keys {
+		prefixed = append(prefixed, n.prefix+key)
+	}
+	return prefixed
+}
+
+// tagKey returns the key under which the version of the tag is stored.
+func (n *Namespaced) tagKey(tag string) string {
+	return n.tagPrefix + tag
+}
+
+// unwrapTagged replaces every value wrapped in an envelope with the value it holds, dropping those stored under a tag
+// that has since been invalidated. Returns the values, the keys that were dropped, and any error that may have
+// occurred.
+func (n *Namespaced) unwrapTagged(ctx context.Context, values map[string][]byte) (map[string][]byte, []string, error) {
+	envelopes := map[string]*taggedEnvelope{}
+	tags := []string{}
+	for key, value := range values {
+		if envelope := decodeEnvelope(value); envelope != nil {
+			envelopes[key] = envelope
+			for tag := range envelope.versions {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	if len(envelopes) == 0 {
+		return values, nil, nil
+	}
+	versions, err := n.getTagVersions(ctx, tags, false, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	stale := []string{}
+	for key, envelope := range envelopes {
+		values[key] = envelope.value
+		for tag, version := range envelope.versions {
+			if versions[tag] != version {
+				delete(values, key)
+				stale = append(stale, key)
+				break
+			}
+		}
+	}
+	return values, stale, nil
+}
+
+// NewNamespaced returns a new cache that prefixes every key with the namespace and version (e.g., "billing:v2:")
+// before passing it on to the provided cache. The version may be blank. Returns the namespaced cache plus any error
+// that may have occurred.
+func NewNamespaced(implementation Contract, namespace string, version string) (*Namespaced, error) {
+	if implementation == nil {
+		return nil, ErrCacheCannotBeNil
+	}
+	if namespace == "" {
+		return nil, ErrNoCacheNamespace
+	}
+	prefix := namespace + ":"
+	if version != "" {
+		prefix += version + ":"
+	}
+	return &Namespaced{
+		implementation: implementation,
+		prefix:         prefix,
+		// every key within the namespace has a colon after the version, so the null byte keeps tags out of reach
+		tagPrefix: strings.TrimSuffix(prefix, ":") + "\x00tags:",
+	}, nil
+}
+
+// taggedEnvelope represents a value stored with tags along with the version of each tag when it was stored. Untagged
+// envelopes have no versions.
+type taggedEnvelope struct {
+	// value is the cached value.
+	value []byte
+
+	// versions maps each tag onto its version when the value was stored.
+	versions map[string]int64
+}
+
+// decodeEnvelope unpacks a value wrapped in a tagged or untagged envelope. Returns nil if the data is not wrapped in an
+// envelope.
+func decodeEnvelope(data []byte) *taggedEnvelope {
+	if !hasEnvelopeMagic(data) || len(data) < 3 {
+		return nil
+	}
+	if data[2] == untaggedEnvelopeVersion {
+		return &taggedEnvelope{
+			value:    data[3:],
+			versions: map[string]int64{},
+		}
+	}
+	if len(data) < 5 || data[2] != taggedEnvelopeVersion {
+		return nil
+	}
+	count := int(binary.BigEndian.Uint16(data[3:5]))
+	envelope := &taggedEnvelope{
+		versions: make(map[string]int64, count),
+	}
+	offset := 5
+	for range count {
+		if len(data) < offset+2 {
+			return nil
+		}
+		length := int(binary.BigEndian.Uint16(data[offset : offset+2]))
+		offset += 2
+		if len(data) < offset+length+8 {
+			return nil
+		}
+		tag := string(data[offset : offset+length])
+		offset += length
+		envelope.versions[tag] = int64(binary.BigEndian.Uint64(data[offset : offset+8]))
+		offset += 8
+	}
+	envelope.value = data[offset:]
+	return envelope
+}
+
+// encodeTaggedEnvelope packs the value along with the version of each of its tags.
+func encodeTaggedEnvelope(tags []string, versions map[string]int64, value []byte) []byte {
+	data := []byte{envelopeMagic[0], envelopeMagic[1], taggedEnvelopeVersion}
+	data = binary.BigEndian.AppendUint16(data, uint16(len(versions)))
+	written := map[string]bool{}
+	for _, tag := range tags {
+		if written[tag] {
+			continue
+		}
+		written[tag] = true
+		data = binary.BigEndian.AppendUint16(data, uint16(len(tag)))
+		data = append(data, tag...)
+		data = binary.BigEndian.AppendUint64(data, uint64(versions[tag]))
+	}
+	return append(data, value...)
+}
+
+// encodeUntaggedValue returns the data stored for a value without tags. The value is returned unchanged unless it
+// starts with the envelope magic, in which case it is wrapped in an untagged envelope.
+func encodeUntaggedValue(value []byte) []byte {
+	if !hasEnvelopeMagic(value) {
+		return value
+	}
+	data := make([]byte, 0, len(value)+3)
+	data = append(data, envelopeMagic[0], envelopeMagic[1], untaggedEnvelopeVersion)
+	return append(data, value...)
+}
+
+// hasEnvelopeMagic returns whether the data starts with the envelope magic.
+func hasEnvelopeMagic(data []byte) bool {
+	return len(data) >= 2 && data[0] == envelopeMagic[0] && data[1] == envelopeMagic[1]
+}
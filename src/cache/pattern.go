@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DefaultScanCount is the number of keys examined by each call to Scan when no other count has been provided.
+const DefaultScanCount = 10
+
+// DeleteByPattern destroys every item whose key matches the glob pattern from the provided cache, scanning and deleting
+// the keys in batches of roughly batchSize keys so that no single command blocks the cache for long. It stops at the
+// first error, including the context being done between batches. The pattern cannot be blank; use "*" to delete
+// every key. Returns the number of items that were deleted plus any error that may have occurred.
+func DeleteByPattern(
+	ctx context.Context, cacheImplementation Contract, pattern string, batchSize int64,
+) (int64, error) {
+	if cacheImplementation == nil {
+		return 0, ErrCacheCannotBeNil
+	}
+	if pattern == "" {
+		return 0, fmt.Errorf("%w: pattern cannot be blank", ErrInvalidPattern)
+	}
+	var cursor uint64
+	var deleted int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return deleted, err
+		}
+		keys, nextCursor, err := cacheImplementation.Scan(ctx, cursor, pattern, batchSize)
+		if err != nil {
+			return deleted, err
+		}
+		if len(keys) > 0 {
+			count, err := cacheImplementation.DeleteMany(ctx, keys...)
+			deleted += count
+			if err != nil {
+				return deleted, err
+			}
+		}
+		if nextCursor == 0 {
+			return deleted, nil
+		}
+		cursor = nextCursor
+	}
+}
+
+// EscapePattern returns the text with every character that has a special meaning within a glob pattern escaped, so
+// that it only matches itself (e.g., for building the pattern "prefix:*" from an arbitrary prefix).
+func EscapePattern(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`).Replace(text)
+}
+
+// MatchPattern reports whether the key matches the glob pattern using the same rules as the Redis SCAN and KEYS
+// commands: "*" matches any run of characters, "?" matches a single character, "[abc]", "[a-z]" and "[^a]" match a
+// single character from (or not from) a set, and "\" escapes the next character. A blank pattern matches every key.
+func MatchPattern(pattern string, key string) bool {
+	if pattern == "" {
+		return true
+	}
+	return matchPattern(pattern, key)
+}
+
+// matchPattern reports whether the key matches the glob pattern, backtracking to the most recent star on a mismatch.
+func matchPattern(pattern string, key string) bool {
+	p, k := 0, 0
+	starPattern, starKey := -1, -1
+	for k < len(key) {
+		if p < len(pattern) {
+			switch pattern[p] {
+			case '*':
+				// collapse consecutive stars and remember where to resume if the rest does not match
+				for p < len(pattern) && pattern[p] == '*' {
+					p++
+				}
+				if p == len(pattern) {
+					return true
+				}
+				starPattern, starKey = p, k
+				continue
+			case '?':
+				p++
+				k++
+				continue
+			case '[':
+				if end, matched := matchClass(pattern, p, key[k]); matched {
+					p = end
+					k++
+					continue
+				}
+			case '\\':
+				if p+1 < len(pattern) && pattern[p+1] == key[k] {
+					p += 2
+					k++
+					continue
+				}
+				if p+1 == len(pattern) && key[k] == '\\' {
+					p++
+					k++
+					continue
+				}
+			default:
+				if pattern[p] == key[k] {
+					p++
+					k++
+					continue
+				}
+			}
+		}
+		if starPattern < 0 {
+			return false
+		}
+		starKey++
+		p, k = starPattern, starKey
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// matchClass matches the character against the class that starts with the "[" at the start index of the pattern.
+// Returns the index just past the closing "]" and whether the character belongs to the class. As with Redis, a class
+// that is not terminated runs until the end of the pattern, an escaped character is always matched literally, and a
+// "-" between any two characters (including "]") forms a range.
+func matchClass(pattern string, start int, character byte) (int, bool) {
+	i := start + 1
+	negate := i < len(pattern) && pattern[i] == '^'
+	if negate {
+		i++
+	}
+	matched := false
+	for i < len(pattern) && pattern[i] != ']' {
+		switch {
+		case pattern[i] == '\\' && i+1 < len(pattern):
+			i++
+			matched = matched || pattern[i] == character
+		case i+2 < len(pattern) && pattern[i+1] == '-':
+			low, high := min(pattern[i], pattern[i+2]), max(pattern[i], pattern[i+2])
+			matched = matched || (character >= low && character <= high)
+			i += 2
+		default:
+			matched = matched || pattern[i] == character
+		}
+		i++
+	}
+	return min(i+1, len(pattern)), matched != negate
+}
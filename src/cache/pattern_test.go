@@ -0,0 +1,104 @@
+package cache
+
+import "testing"
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		key     string
+		want    bool
+	}{
+		{"blank pattern", "", "anything", true},
+		{"literal", "user:1", "user:1", true},
+		{"literal mismatch", "user:1", "user:2", false},
+		{"literal prefix only", "user", "user:1", false},
+		{"star matches blank", "*", "", true},
+		{"star matches everything", "*", "user:1", true},
+		{"star in the middle", "a*c", "abbbc", true},
+		{"star must reach the end", "a*c", "abcd", false},
+		{"consecutive stars", "a**b", "ab", true},
+		{"several stars backtrack", "*:*:x", "a:b:c:x", true},
+		{"question mark", "a?c", "abc", true},
+		{"question mark needs a character", "a?c", "ac", false},
+		{"question mark matches one character", "?", "ab", false},
+		{"range", "[a-z]", "m", true},
+		{"range excludes", "[a-z]", "M", false},
+		{"reversed range", "[z-a]", "m", true},
+		{"set", "[abc]", "b", true},
+		{"set excludes", "[abc]", "d", false},
+		{"set and range", "user:[0-9x]", "user:x", true},
+		{"empty class", "[]", "a", false},
+		{"negated", "[^a]", "b", true},
+		{"negated excludes", "[^a]", "a", false},
+		{"negated range", "[^a-c]x", "dx", true},
+		{"negated range excludes", "[^a-c]x", "bx", false},
+		{"escaped star", `\*`, "*", true},
+		{"escaped star is literal", `\*`, "a", false},
+		{"escaped question mark", `a\?`, "a?", true},
+		{"escaped question mark is literal", `a\?`, "ab", false},
+		{"escaped bracket", `\[a]`, "[a]", true},
+		{"escaped backslash", `a\\b`, `a\b`, true},
+		{"trailing backslash", `a\`, `a\`, true},
+		{"escaped closing bracket in class", `[\]]`, "]", true},
+		{"escaped dash in class", `[\-]`, "-", true},
+		{"escaped dash is not a range", `[a\-z]`, "m", false},
+		{"range ending with a bracket", "[a-]", "]", true},
+		{"range ending with a bracket excludes", "[a-]", "b", false},
+		{"unterminated class", "[abc", "a", true},
+		{"unterminated class excludes", "[abc", "d", false},
+		{"unterminated class is not a literal bracket", "[abc", "[", false},
+		{"unterminated range", "x[a-c", "xb", true},
+		{"unterminated empty class", "[", "[", false},
+		{"unterminated negated class", "[^", "a", true},
+		{"class needs a character", "[a-z]", "", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := MatchPattern(test.pattern, test.key); got != test.want {
+				t.Errorf("MatchPattern(%q, %q) = %v, want %v", test.pattern, test.key, got, test.want)
+			}
+		})
+	}
+}
+
+func TestMatchClass(t *testing.T) {
+	tests := []struct {
+		name        string
+		pattern     string
+		character   byte
+		wantEnd     int
+		wantMatched bool
+	}{
+		{"terminated", "[a-c]x", 'b', 5, true},
+		{"terminated excludes", "[a-c]x", 'd', 5, false},
+		{"negated", "[^a]x", 'a', 4, false},
+		{"escaped closing bracket", `[\]]x`, ']', 4, true},
+		{"unterminated runs to the end", "[abc", 'c', 4, true},
+		{"unterminated negated", "[^abc", 'd', 5, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			end, matched := matchClass(test.pattern, 0, test.character)
+			if end != test.wantEnd || matched != test.wantMatched {
+				t.Errorf("matchClass(%q, 0, %q) = (%d, %v), want (%d, %v)", test.pattern, test.character, end, matched,
+					test.wantEnd, test.wantMatched)
+			}
+		})
+	}
+}
+
+func TestEscapePattern(t *testing.T) {
+	tests := []string{"plain", "a*b", "a?b", "[abc]", `back\slash`, `all*?[]\`}
+	for _, text := range tests {
+		t.Run(text, func(t *testing.T) {
+			pattern := EscapePattern(text)
+			if !MatchPattern(pattern, text) {
+				t.Errorf("MatchPattern(%q, %q) = false, want the escaped text to match itself", pattern, text)
+			}
+			if MatchPattern(pattern, text+"x") || MatchPattern(pattern, "x"+text[1:]) {
+				t.Errorf("MatchPattern(%q) matched text other than %q", pattern, text)
+			}
+		})
+	}
+}
@@ -2,31 +2,124 @@ package cache
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"os"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// RedisMode represents the way the Redis servers are deployed and therefore how the client connects to them.
+type RedisMode string
+
+const (
+	// RedisModeCluster connects to a Redis Cluster, discovering every node from the given addresses.
+	RedisModeCluster RedisMode = "cluster"
+
+	// RedisModeSentinel connects to the master of a Redis Sentinel deployment, following it across failovers.
+	RedisModeSentinel RedisMode = "sentinel"
+
+	// RedisModeStandalone connects to a single Redis server.
+	RedisModeStandalone RedisMode = "standalone"
+)
+
 // RedisConnectionArguments is a struct representing the general properties expected when making a connection
 // to a Redis cache.
+//
+// The struct tags allow the arguments to be filled from the service configuration with config.Bind(). The address and
+// passwords are intentionally left untagged because they are composed from multiple properties and resolved separately
+// as secrets, respectively.
 type RedisConnectionArguments struct {
-	// CacheIdentifier in the embedded struct refers to the Redis database ID.
+	// CacheIdentifier in the embedded struct refers to the Redis database ID. It must be zero in cluster mode.
 	CacheConnectionArguments
 
-	Addr     string
+	// Addr is the "host:port" address of the Redis server. It is also used as the only sentinel or cluster node address
+	// when Addrs is empty.
+	Addr string
+
+	// Addrs are the "host:port" addresses of the sentinels (sentinel mode) or of the nodes from which the rest of the
+	// cluster is discovered (cluster mode). It is ignored in standalone mode.
+	Addrs []string `config:"CACHE_ADDRESSES"`
+
+	// Mode describes how the Redis servers are deployed. Defaults to RedisModeStandalone.
+	Mode RedisMode `config:"CACHE_REDIS_MODE"`
+
 	Password string
-	Username string
+
+	// SentinelMasterName is the name of the master monitored by the sentinels. It is required in sentinel mode.
+	SentinelMasterName string `config:"CACHE_SENTINEL_MASTER"`
+
+	// SentinelPassword is the password used to authenticate with the sentinels, which may differ from the password of
+	// the master.
+	SentinelPassword string
+
+	// SentinelUsername is the username used to authenticate with the sentinels.
+	SentinelUsername string `config:"CACHE_SENTINEL_USERNAME"`
+
+	// TLS describes whether and how connections are secured with TLS.
+	TLS RedisTLSArguments
+
+	Username string `config:"CACHE_USERNAME"`
 }
 
-// Redis represents a Redis caching mechanism.
+// RedisTLSArguments is a struct representing the TLS settings used when making a connection to a Redis cache.
+type RedisTLSArguments struct {
+	// CAFile is the path of a PEM file holding the certificate authorities used to verify the servers instead of the
+	// system pool.
+	CAFile string `config:"CACHE_TLS_CA_FILE"`
+
+	// CertFile is the path of a PEM file holding the client certificate. It must be set together with KeyFile.
+	CertFile string `config:"CACHE_TLS_CERT_FILE"`
+
+	// Enabled describes whether connections are secured with TLS.
+	Enabled bool `config:"CACHE_TLS_ENABLED"`
+
+	// InsecureSkipVerify turns off verification of the server certificates. It should only be used for development.
+	InsecureSkipVerify bool `config:"CACHE_TLS_INSECURE_SKIP_VERIFY"`
+
+	// KeyFile is the path of a PEM file holding the private key of the client certificate.
+	KeyFile string `config:"CACHE_TLS_KEY_FILE"`
+
+	// ServerName is the name used to verify the server certificates. Defaults to the host being connected to.
+	ServerName string `config:"CACHE_TLS_SERVER_NAME"`
+}
+
+const (
+	// redisClusterCursorMask selects the bits of a cluster scan cursor that hold the cursor of the master being
+	// scanned.
+	redisClusterCursorMask = 1<<redisClusterNodeShift - 1
+
+	// redisClusterNodeShift is the position of the bits of a cluster scan cursor that hold the index of the master
+	// being scanned.
+	redisClusterNodeShift = 48
+)
+
+// incrementWithTTLScript increments the key and applies the TTL only if the key does not already expire, so that fixed
+// windows are not extended by later increments. It behaves like INCRBY followed by PEXPIRE with the NX option, which
+// would require Redis 7.
+var incrementWithTTLScript = redis.NewScript(`
+local value = redis.call("incrby", KEYS[1], ARGV[1])
+if redis.call("pttl", KEYS[1]) == -1 then
+	redis.call("pexpire", KEYS[1], ARGV[2])
+end
+return value
+`)
+
+// Redis represents a Redis caching mechanism. The same operations are available whether it is connected to a single
+// server, a Sentinel deployment or a cluster.
 type Redis struct {
-	client *redis.Client
+	client redis.UniversalClient
 }
 
-// Client returns the underlying redis.Client pointer for this Redis cache instance for extendability purposes.
-func (r *Redis) Client() *redis.Client {
+// Client returns the underlying redis.UniversalClient for this Redis cache instance for extendability purposes. Its
+// concrete type depends on the mode (e.g., *redis.Client in standalone mode or *redis.ClusterClient in cluster mode).
+func (r *Redis) Client() redis.UniversalClient {
 	if r == nil {
 		return nil
 	}
@@ -41,6 +134,24 @@ func (r *Redis) Close() error {
 	return r.client.Close()
 }
 
+// Decrement atomically decrements the integer value associated with the given key by the delta and returns the new
+// value. A missing key is treated as zero.
+func (r *Redis) Decrement(ctx context.Context, key string, delta int64) (int64, error) {
+	if r == nil || r.client == nil {
+		return 0, nil
+	}
+	return r.client.DecrBy(ctx, key, delta).Result()
+}
+
+// DecrementWithTTL performs the same operation as Decrement but also applies the time-to-live (TTL) duration if the
+// key does not already expire. Both commands are sent within a single transaction.
+func (r *Redis) DecrementWithTTL(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	if r == nil || r.client == nil {
+		return 0, nil
+	}
+	return r.IncrementWithTTL(ctx, key, -delta, ttl)
+}
+
 // Delete destroys the item associated with the given key from the cache. The integer return value indicates the number
 // of items that were deleted.
 func (r *Redis) Delete(ctx context.Context, key string) (int64, error) {
@@ -50,6 +161,29 @@ func (r *Redis) Delete(ctx context.Context, key string) (int64, error) {
 	return r.client.Del(ctx, key).Result()
 }
 
+// DeleteMany destroys the items associated with the given keys from the cache using a single pipeline. The integer
+// return value indicates the number of items that were deleted.
+func (r *Redis) DeleteMany(ctx context.Context, keys ...string) (int64, error) {
+	if r == nil || r.client == nil || len(keys) == 0 {
+		return 0, nil
+	}
+	commands := make([]*redis.IntCmd, 0, len(keys))
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			commands = append(commands, pipe.Del(ctx, key))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	for _, command := range commands {
+		count += command.Val()
+	}
+	return count, nil
+}
+
 // Exists checks if an item with the given key exists in the cache.
 func (r *Redis) Exists(ctx context.Context, key string) (bool, error) {
 	if r == nil || r.client == nil {
@@ -59,6 +193,19 @@ func (r *Redis) Exists(ctx context.Context, key string) (bool, error) {
 	return result > 0, err
 }
 
+// Expire sets the time-to-live (TTL) duration of the item associated with the given key. A non-positive TTL removes
+// the item. Returns whether the key exists.
+func (r *Redis) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	if r == nil || r.client == nil {
+		return false, nil
+	}
+	if ttl <= 0 {
+		count, err := r.client.Del(ctx, key).Result()
+		return count > 0, err
+	}
+	return r.client.PExpire(ctx, key, ttl).Result()
+}
+
 // Get retrieves the item associated with the given key from the cache. If the key could not be found, this method
 // returns nil.
 func (r *Redis) Get(ctx context.Context, key string) ([]byte, error) {
@@ -72,6 +219,116 @@ func (r *Redis) Get(ctx context.Context, key string) ([]byte, error) {
 	return result, err
 }
 
+// GetAndDelete atomically retrieves and removes the item associated with the given key from the cache. If the key
+// could not be found, this method returns nil.
+func (r *Redis) GetAndDelete(ctx context.Context, key string) ([]byte, error) {
+	if r == nil || r.client == nil {
+		return nil, nil
+	}
+	result, err := r.client.GetDel(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	return result, err
+}
+
+// GetMany retrieves the items associated with the given keys from the cache using a single pipeline. Keys that could
+// not be found are omitted from the returned map.
+func (r *Redis) GetMany(ctx context.Context, keys ...string) (map[string][]byte, error) {
+	values := map[string][]byte{}
+	if r == nil || r.client == nil || len(keys) == 0 {
+		return values, nil
+	}
+	commands := make([]*redis.StringCmd, 0, len(keys))
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			commands = append(commands, pipe.Get(ctx, key))
+		}
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+	for i, command := range commands {
+		result, err := command.Bytes()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		values[keys[i]] = result
+	}
+	return values, nil
+}
+
+// Increment atomically increments the integer value associated with the given key by the delta and returns the new
+// value. A missing key is treated as zero.
+func (r *Redis) Increment(ctx context.Context, key string, delta int64) (int64, error) {
+	if r == nil || r.client == nil {
+		return 0, nil
+	}
+	return r.client.IncrBy(ctx, key, delta).Result()
+}
+
+// IncrementWithTTL performs the same operation as Increment but also applies the time-to-live (TTL) duration if the
+// key does not already expire. Both commands are sent within a single transaction.
+func (r *Redis) IncrementWithTTL(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	if r == nil || r.client == nil {
+		return 0, nil
+	}
+	if ttl <= 0 {
+		return r.Increment(ctx, key, delta)
+	}
+	return incrementWithTTLScript.Run(ctx, r.client, []string{key}, delta, max(ttl.Milliseconds(), 1)).Int64()
+}
+
+// Scan returns a page of the keys that match the glob pattern using the SCAN command, examining roughly count keys,
+// along with the cursor from which to continue.
+//
+// In cluster mode every master is scanned in turn, ordered by address. The index of the master being scanned is kept
+// within the highest bits of the cursor (see redisClusterNodeShift), so the cluster should not gain or lose masters
+// during an iteration.
+func (r *Redis) Scan(ctx context.Context, cursor uint64, pattern string, count int64) ([]string, uint64, error) {
+	if r == nil || r.client == nil {
+		return nil, 0, nil
+	}
+	if count <= 0 {
+		count = DefaultScanCount
+	}
+	if pattern == "" {
+		pattern = "*"
+	}
+	clusterClient, isCluster := r.client.(*redis.ClusterClient)
+	if !isCluster {
+		return r.client.Scan(ctx, cursor, pattern, count).Result()
+	}
+	masters, err := getClusterMasters(ctx, clusterClient)
+	if err != nil {
+		return nil, 0, err
+	}
+	node := int(cursor >> redisClusterNodeShift)
+	if node >= len(masters) {
+		return nil, 0, nil
+	}
+	keys, nodeCursor, err := masters[node].Scan(ctx, cursor&redisClusterCursorMask, pattern, count).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+	if nodeCursor > redisClusterCursorMask {
+		return nil, 0, fmt.Errorf("scan cursor %d of cluster node %d exceeds %d bits", nodeCursor, node,
+			redisClusterNodeShift)
+	}
+	if nodeCursor == 0 {
+		// this master is done, so continue from the start of the next one
+		node++
+		if node >= len(masters) {
+			return keys, 0, nil
+		}
+	}
+	return keys, uint64(node)<<redisClusterNodeShift | nodeCursor, nil
+}
+
 // Set stores the given value associated with the given key in the cache.
 func (r *Redis) Set(ctx context.Context, key string, value []byte) error {
 	if r == nil || r.client == nil {
@@ -80,6 +337,31 @@ func (r *Redis) Set(ctx context.Context, key string, value []byte) error {
 	return r.SetWithTTL(ctx, key, value, 0)
 }
 
+// SetIfNotExists stores the given value associated with the given key in the cache along with a time-to-live (TTL)
+// duration, but only if the key does not already exist. A zero TTL means the item never expires. Returns whether the
+// value was stored.
+func (r *Redis) SetIfNotExists(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	if r == nil || r.client == nil {
+		return false, nil
+	}
+	return r.client.SetNX(ctx, key, value, ttl).Result()
+}
+
+// SetMany stores each of the given values associated with its key in the cache along with a time-to-live (TTL)
+// duration using a single pipeline. A zero TTL means the items never expire.
+func (r *Redis) SetMany(ctx context.Context, values map[string][]byte, ttl time.Duration) error {
+	if r == nil || r.client == nil || len(values) == 0 {
+		return nil
+	}
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for key, value := range values {
+			pipe.Set(ctx, key, value, ttl)
+		}
+		return nil
+	})
+	return err
+}
+
 // SetWithTTL stores the given value associated with the given key in the cache along with a time-to-live (TTL)
 // duration.
 func (r *Redis) SetWithTTL(ctx context.Context, key string, value []byte, ttl time.Duration) error {
@@ -89,27 +371,77 @@ func (r *Redis) SetWithTTL(ctx context.Context, key string, value []byte, ttl ti
 	return r.client.Set(ctx, key, value, ttl).Err()
 }
 
+// TTL returns the remaining time-to-live (TTL) duration of the item associated with the given key. Returns
+// TTLNoExpiry if the item never expires and TTLKeyNotFound if the key does not exist.
+func (r *Redis) TTL(ctx context.Context, key string) (time.Duration, error) {
+	if r == nil || r.client == nil {
+		return TTLKeyNotFound, nil
+	}
+	return r.client.PTTL(ctx, key).Result()
+}
+
+// getWithTTL retrieves the item associated with the given key along with its remaining time-to-live (TTL) duration
+// using a single pipeline. If the key could not be found, this method returns a nil value and TTLKeyNotFound.
+func (r *Redis) getWithTTL(ctx context.Context, key string) ([]byte, time.Duration, error) {
+	if r == nil || r.client == nil {
+		return nil, TTLKeyNotFound, nil
+	}
+	var get *redis.StringCmd
+	var ttl *redis.DurationCmd
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, key)
+		ttl = pipe.PTTL(ctx, key)
+		return nil
+	})
+	if err == redis.Nil {
+		return nil, TTLKeyNotFound, nil
+	}
+	if err != nil {
+		return nil, TTLKeyNotFound, err
+	}
+	return []byte(get.Val()), ttl.Val(), nil
+}
+
 // NewRedis creates and returns a new Redis cache instance along with any error that may have occurred.
 func NewRedis(ctx context.Context, connectionArguments *RedisConnectionArguments) (*Redis, error) {
 	err := ValidateRedisConnectionArguments(connectionArguments)
 	if err != nil {
 		return nil, err
 	}
-	redisOptions := &redis.Options{
-		Addr: connectionArguments.Addr,
-	}
 	databaseID, err := strconv.ParseInt(connectionArguments.CacheIdentifier, 10, 64)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrRedisCannotParseDatabaseIDAsInteger, err)
 	}
-	redisOptions.DB = int(databaseID)
+	tlsConfig, err := makeRedisTLSConfig(&connectionArguments.TLS)
+	if err != nil {
+		return nil, err
+	}
+	redisOptions := &redis.UniversalOptions{
+		Addrs:     []string{connectionArguments.Addr},
+		DB:        int(databaseID),
+		TLSConfig: tlsConfig,
+	}
+	switch connectionArguments.Mode {
+	case RedisModeCluster:
+		redisOptions.IsClusterMode = true
+		if len(connectionArguments.Addrs) > 0 {
+			redisOptions.Addrs = connectionArguments.Addrs
+		}
+	case RedisModeSentinel:
+		redisOptions.MasterName = connectionArguments.SentinelMasterName
+		redisOptions.SentinelPassword = connectionArguments.SentinelPassword
+		redisOptions.SentinelUsername = connectionArguments.SentinelUsername
+		if len(connectionArguments.Addrs) > 0 {
+			redisOptions.Addrs = connectionArguments.Addrs
+		}
+	}
 	// Set credentials provider if username or password is provided because auth is optional
 	if connectionArguments.Username != "" || connectionArguments.Password != "" {
 		redisOptions.CredentialsProviderContext = func(ctx context.Context) (string, string, error) {
 			return connectionArguments.Username, connectionArguments.Password, nil
 		}
 	}
-	return NewRedisWithOptions(ctx, redisOptions)
+	return newRedisFromClient(ctx, redis.NewUniversalClient(redisOptions))
 }
 
 // NewRedisWithOptions creates and returns a new Redis cache instance along with any error that may have occurred.
@@ -117,14 +449,16 @@ func NewRedis(ctx context.Context, connectionArguments *RedisConnectionArguments
 // The provided redis.Options pointer is used to configure the underlying Redis client fully and to give additional
 // flexibility past what the NewRedis() function provides.
 func NewRedisWithOptions(ctx context.Context, options *redis.Options) (*Redis, error) {
-	client := redis.NewClient(options)
-	_, err := client.Ping(ctx).Result()
-	if err != nil {
-		return nil, fmt.Errorf("%w: %w", ErrCannotConnect, err)
-	}
-	return &Redis{
-		client: client,
-	}, nil
+	return newRedisFromClient(ctx, redis.NewClient(options))
+}
+
+// NewRedisWithUniversalOptions creates and returns a new Redis cache instance along with any error that may have
+// occurred.
+//
+// The provided redis.UniversalOptions pointer is used to configure the underlying Redis client fully, selecting a
+// standalone, Sentinel or cluster client the same way redis.NewUniversalClient() does.
+func NewRedisWithUniversalOptions(ctx context.Context, options *redis.UniversalOptions) (*Redis, error) {
+	return newRedisFromClient(ctx, redis.NewUniversalClient(options))
 }
 
 // ValidateRedisConnectionArguments takes a RedisConnectionArguments struct pointer and returns an error
@@ -136,8 +470,95 @@ func ValidateRedisConnectionArguments(connectionArguments *RedisConnectionArgume
 	if connectionArguments.CacheIdentifier == "" {
 		return ErrNoCacheIdentifier
 	}
-	if connectionArguments.Addr == "" {
-		return ErrRedisNoConnectionAddr
+	switch connectionArguments.Mode {
+	case "", RedisModeStandalone:
+		if connectionArguments.Addr == "" {
+			return ErrRedisNoConnectionAddr
+		}
+	case RedisModeCluster:
+		if connectionArguments.Addr == "" && len(connectionArguments.Addrs) == 0 {
+			return ErrRedisNoConnectionAddr
+		}
+		if databaseID, err := strconv.ParseInt(connectionArguments.CacheIdentifier, 10, 64); err == nil && databaseID != 0 {
+			return fmt.Errorf("%w: %d", ErrRedisClusterNonZeroDatabase, databaseID)
+		}
+	case RedisModeSentinel:
+		if connectionArguments.Addr == "" && len(connectionArguments.Addrs) == 0 {
+			return ErrRedisNoConnectionAddr
+		}
+		if connectionArguments.SentinelMasterName == "" {
+			return ErrRedisNoSentinelMasterName
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrRedisInvalidMode, connectionArguments.Mode)
+	}
+	if (connectionArguments.TLS.CertFile == "") != (connectionArguments.TLS.KeyFile == "") {
+		return fmt.Errorf("%w: certificate and key files must be set together", ErrRedisInvalidTLSConfiguration)
 	}
 	return nil
 }
+
+// makeRedisTLSConfig builds the TLS configuration described by the arguments. Returns nil if TLS is not enabled, plus
+// any error that may have occurred.
+func makeRedisTLSConfig(tlsArguments *RedisTLSArguments) (*tls.Config, error) {
+	if tlsArguments == nil || !tlsArguments.Enabled {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: tlsArguments.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         tlsArguments.ServerName,
+	}
+	if tlsArguments.CAFile != "" {
+		contents, err := os.ReadFile(tlsArguments.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrRedisInvalidTLSConfiguration, err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(contents) {
+			return nil, fmt.Errorf("%w: no certificates found in %s", ErrRedisInvalidTLSConfiguration,
+				tlsArguments.CAFile)
+		}
+	}
+	if tlsArguments.CertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(tlsArguments.CertFile, tlsArguments.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrRedisInvalidTLSConfiguration, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return tlsConfig, nil
+}
+
+// getClusterMasters returns a client for every master of the cluster, ordered by address.
+func getClusterMasters(ctx context.Context, clusterClient *redis.ClusterClient) ([]*redis.Client, error) {
+	var mu sync.Mutex
+	masters := []*redis.Client{}
+	err := clusterClient.ForEachMaster(ctx, func(ctx context.Context, master *redis.Client) error {
+		// ensure we don't get a collision if two or more goroutines try to write concurrently
+		mu.Lock()
+		defer mu.Unlock()
+		masters = append(masters, master)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(masters, func(a, b *redis.Client) int {
+		return strings.Compare(a.Options().Addr, b.Options().Addr)
+	})
+	return masters, nil
+}
+
+// newRedisFromClient checks that the client can reach Redis and wraps it in a new Redis cache instance, closing the
+// client if it cannot. Returns the Redis cache along with any error that may have occurred.
+func newRedisFromClient(ctx context.Context, client redis.UniversalClient) (*Redis, error) {
+	_, err := client.Ping(ctx).Result()
+	if err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("%w: %w", ErrCannotConnect, err)
+	}
+	return &Redis{
+		client: client,
+	}, nil
+}
@@ -0,0 +1,496 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultTieredInvalidationChannel is the Redis pub/sub channel used to broadcast invalidations when no other channel
+// has been provided.
+const DefaultTieredInvalidationChannel = "cache:invalidations"
+
+// DefaultTieredL1TTL is the longest time an item is held by the local tier when no other duration has been provided.
+const DefaultTieredL1TTL time.Duration = 30 * time.Second
+
+// tieredResubscribeDelay is how long the invalidation listener waits before receiving again after an error.
+const tieredResubscribeDelay time.Duration = time.Second
+
+// TieredConnectionArguments is a struct representing the properties expected when creating a two-tier cache.
+//
+// The struct tags allow the arguments to be filled from the service configuration with config.Bind().
+type TieredConnectionArguments struct {
+	// InvalidationChannel is the Redis pub/sub channel on which invalidations are broadcast. Every instance sharing
+	// the same Redis cache must use the same channel. Defaults to DefaultTieredInvalidationChannel.
+	InvalidationChannel string `config:"CACHE_INVALIDATION_CHANNEL"`
+
+	// L1 contains the arguments of the local in-memory tier. Bounding its size with MaxEntries is recommended.
+	L1 MemoryConnectionArguments
+
+	// L1TTL is the longest time an item is held by the local tier. It bounds how stale the local tier can become if an
+	// invalidation is missed. Defaults to DefaultTieredL1TTL.
+	L1TTL time.Duration `config:"CACHE_L1_TTL"`
+}
+
+// TierStats is a struct representing the hit and miss counts of a single cache tier.
+type TierStats struct {
+	// Hits is the number of lookups that were answered by the tier.
+	Hits uint64
+
+	// Misses is the number of lookups that the tier could not answer.
+	Misses uint64
+}
+
+// TieredStats is a struct representing the hit and miss counts of both tiers of a two-tier cache.
+type TieredStats struct {
+	// L1 contains the counts of the local in-memory tier.
+	L1 TierStats
+
+	// L2 contains the counts of the Redis tier.
+	L2 TierStats
+}
+
+// tieredInvalidation represents an invalidation message broadcast over Redis pub/sub.
+type tieredInvalidation struct {
+	// Keys are the keys whose local copies must be removed.
+	Keys []string `json:"keys"`
+
+	// Origin is the identifier of the instance that sent the message.
+	Origin string `json:"origin"`
+}
+
+// tierCounters holds the hit and miss counts of a single cache tier.
+type tierCounters struct {
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// Tiered represents a two-tier caching mechanism made up of a small in-process Memory cache (L1) in front of a Redis
+// cache (L2). Reads are answered by the local tier when possible. Every write and delete goes to Redis, removes the
+// local copy and broadcasts an invalidation over Redis pub/sub so that every other instance removes its local copy
+// too.
+type Tiered struct {
+	cancel     context.CancelFunc
+	channel    string
+	closeOnce  sync.Once
+	done       chan struct{}
+	generation atomic.Uint64
+	instanceID string
+	l1         *Memory
+	l1Stats    tierCounters
+	l1TTL      time.Duration
+	l2         *Redis
+	l2Stats    tierCounters
+	pubsub     *redis.PubSub
+}
+
+// Close stops listening for invalidations and closes both tiers.
+func (t *Tiered) Close() error {
+	if t == nil {
+		return nil
+	}
+	var err error
+	t.closeOnce.Do(func() {
+		// closing the subscription interrupts the listener if it is waiting for a message
+		t.cancel()
+		_ = t.pubsub.Close()
+		<-t.done
+		err = errors.Join(t.l1.Close(), t.l2.Close())
+	})
+	return err
+}
+
+// Decrement atomically decrements the integer value associated with the given key by the delta and returns the new
+// value. A missing key is treated as zero.
+func (t *Tiered) Decrement(ctx context.Context, key string, delta int64) (int64, error) {
+	if t == nil {
+		return 0, nil
+	}
+	defer t.invalidate(ctx, key)
+	return t.l2.Decrement(ctx, key, delta)
+}
+
+// DecrementWithTTL performs the same operation as Decrement but also applies the time-to-live (TTL) duration if the
+// key does not already expire.
+func (t *Tiered) DecrementWithTTL(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	if t == nil {
+		return 0, nil
+	}
+	defer t.invalidate(ctx, key)
+	return t.l2.DecrementWithTTL(ctx, key, delta, ttl)
+}
+
+// Delete destroys the item associated with the given key from the cache. The integer return value indicates the number
+// of items that were deleted.
+func (t *Tiered) Delete(ctx context.Context, key string) (int64, error) {
+	if t == nil {
+		return 0, nil
+	}
+	defer t.invalidate(ctx, key)
+	return t.l2.Delete(ctx, key)
+}
+
+// DeleteMany destroys the items associated with the given keys from the cache. The integer return value indicates
+// the number of items that were deleted.
+func (t *Tiered) DeleteMany(ctx context.Context, keys ...string) (int64, error) {
+	if t == nil {
+		return 0, nil
+	}
+	defer t.invalidate(ctx, keys...)
+	return t.l2.DeleteMany(ctx, keys...)
+}
+
+// Exists checks if an item with the given key exists in the cache.
+func (t *Tiered) Exists(ctx context.Context, key string) (bool, error) {
+	if t == nil {
+		return false, nil
+	}
+	if exists, err := t.l1.Exists(ctx, key); err == nil && exists {
+		return true, nil
+	}
+	return t.l2.Exists(ctx, key)
+}
+
+// Expire sets the time-to-live (TTL) duration of the item associated with the given key. A non-positive TTL removes
+// the item. Returns whether the key exists.
+func (t *Tiered) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	if t == nil {
+		return false, nil
+	}
+	defer t.invalidate(ctx, key)
+	return t.l2.Expire(ctx, key, ttl)
+}
+
+// Get retrieves the item associated with the given key from the local tier, falling back to Redis on a miss. Items
+// read from Redis are copied into the local tier for no longer than the L1 TTL or their remaining TTL, whichever is
+// shorter. If the key could not be found, this method returns nil.
+func (t *Tiered) Get(ctx context.Context, key string) ([]byte, error) {
+	if t == nil {
+		return nil, nil
+	}
+	value, err := t.l1.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if value != nil {
+		t.l1Stats.hits.Add(1)
+		return value, nil
+	}
+	t.l1Stats.misses.Add(1)
+
+	// an invalidation that arrives while Redis is being read may be for the value being read, in which case it must not
+	// be copied into the local tier
+	generation := t.generation.Load()
+	value, ttl, err := t.l2.getWithTTL(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		t.l2Stats.misses.Add(1)
+		return nil, nil
+	}
+	t.l2Stats.hits.Add(1)
+	if ttl == TTLKeyNotFound {
+		return value, nil
+	}
+	if ttl == TTLNoExpiry || ttl > t.l1TTL {
+		ttl = t.l1TTL
+	}
+	if t.generation.Load() == generation {
+		_ = t.l1.SetWithTTL(ctx, key, value, ttl)
+
+		// remove the copy again if an invalidation arrived while it was being stored
+		if t.generation.Load() != generation {
+			_, _ = t.l1.Delete(ctx, key)
+		}
+	}
+	return value, nil
+}
+
+// GetAndDelete atomically retrieves and removes the item associated with the given key from the cache. If the key
+// could not be found, this method returns nil.
+func (t *Tiered) GetAndDelete(ctx context.Context, key string) ([]byte, error) {
+	if t == nil {
+		return nil, nil
+	}
+	defer t.invalidate(ctx, key)
+	return t.l2.GetAndDelete(ctx, key)
+}
+
+// GetMany retrieves the items associated with the given keys from the local tier, falling back to Redis for the keys
+// that are missing locally. Items read from Redis are not copied into the local tier. Keys that could not be found are
+// omitted from the returned map.
+func (t *Tiered) GetMany(ctx context.Context, keys ...string) (map[string][]byte, error) {
+	if t == nil {
+		return map[string][]byte{}, nil
+	}
+	values, err := t.l1.GetMany(ctx, keys...)
+	if err != nil {
+		return map[string][]byte{}, err
+	}
+	missing := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if _, found := values[key]; !found {
+			missing = append(missing, key)
+		}
+	}
+	t.l1Stats.hits.Add(uint64(len(keys) - len(missing)))
+	t.l1Stats.misses.Add(uint64(len(missing)))
+	if len(missing) == 0 {
+		return values, nil
+	}
+	remote, err := t.l2.GetMany(ctx, missing...)
+	if err != nil {
+		return map[string][]byte{}, err
+	}
+	t.l2Stats.hits.Add(uint64(len(remote)))
+	t.l2Stats.misses.Add(uint64(len(missing) - len(remote)))
+	for key, value := range remote {
+		values[key] = value
+	}
+	return values, nil
+}
+
+// Increment atomically increments the integer value associated with the given key by the delta and returns the new
+// value. A missing key is treated as zero.
+func (t *Tiered) Increment(ctx context.Context, key string, delta int64) (int64, error) {
+	if t == nil {
+		return 0, nil
+	}
+	defer t.invalidate(ctx, key)
+	return t.l2.Increment(ctx, key, delta)
+}
+
+// IncrementWithTTL performs the same operation as Increment but also applies the time-to-live (TTL) duration if the
+// key does not already expire.
+func (t *Tiered) IncrementWithTTL(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	if t == nil {
+		return 0, nil
+	}
+	defer t.invalidate(ctx, key)
+	return t.l2.IncrementWithTTL(ctx, key, delta, ttl)
+}
+
+// L1 returns the local in-memory tier.
+func (t *Tiered) L1() *Memory {
+	if t == nil {
+		return nil
+	}
+	return t.l1
+}
+
+// L2 returns the Redis tier.
+func (t *Tiered) L2() *Redis {
+	if t == nil {
+		return nil
+	}
+	return t.l2
+}
+
+// Scan returns a page of the keys within the Redis tier that match the glob pattern, examining roughly count keys,
+// along with the cursor from which to continue.
+func (t *Tiered) Scan(ctx context.Context, cursor uint64, pattern string, count int64) ([]string, uint64, error) {
+	if t == nil {
+		return nil, 0, nil
+	}
+	return t.l2.Scan(ctx, cursor, pattern, count)
+}
+
+// Set stores the given value associated with the given key in the cache.
+func (t *Tiered) Set(ctx context.Context, key string, value []byte) error {
+	if t == nil {
+		return nil
+	}
+	defer t.invalidate(ctx, key)
+	return t.l2.Set(ctx, key, value)
+}
+
+// SetIfNotExists stores the given value associated with the given key in the cache along with a time-to-live (TTL)
+// duration, but only if the key does not already exist. A zero TTL means the item never expires. Returns whether the
+// value was stored.
+func (t *Tiered) SetIfNotExists(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	if t == nil {
+		return false, nil
+	}
+	stored, err := t.l2.SetIfNotExists(ctx, key, value, ttl)
+	if stored {
+		t.invalidate(ctx, key)
+	}
+	return stored, err
+}
+
+// SetMany stores each of the given values associated with its key in the cache along with a time-to-live (TTL)
+// duration. A zero TTL means the items never expire.
+func (t *Tiered) SetMany(ctx context.Context, values map[string][]byte, ttl time.Duration) error {
+	if t == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	defer t.invalidate(ctx, keys...)
+	return t.l2.SetMany(ctx, values, ttl)
+}
+
+// SetWithTTL stores the given value associated with the given key in the cache along with a time-to-live (TTL)
+// duration.
+func (t *Tiered) SetWithTTL(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if t == nil {
+		return nil
+	}
+	defer t.invalidate(ctx, key)
+	return t.l2.SetWithTTL(ctx, key, value, ttl)
+}
+
+// Stats returns the hit and miss counts of both tiers since the cache was created.
+func (t *Tiered) Stats() TieredStats {
+	if t == nil {
+		return TieredStats{}
+	}
+	return TieredStats{
+		L1: TierStats{
+			Hits:   t.l1Stats.hits.Load(),
+			Misses: t.l1Stats.misses.Load(),
+		},
+		L2: TierStats{
+			Hits:   t.l2Stats.hits.Load(),
+			Misses: t.l2Stats.misses.Load(),
+		},
+	}
+}
+
+// TTL returns the remaining time-to-live (TTL) duration of the item associated with the given key as reported by
+// Redis. Returns TTLNoExpiry if the item never expires and TTLKeyNotFound if the key does not exist.
+func (t *Tiered) TTL(ctx context.Context, key string) (time.Duration, error) {
+	if t == nil {
+		return TTLKeyNotFound, nil
+	}
+	return t.l2.TTL(ctx, key)
+}
+
+// evict removes the local copies of the given keys and marks that an invalidation has happened.
+func (t *Tiered) evict(keys ...string) {
+	t.generation.Add(1)
+	_, _ = t.l1.DeleteMany(context.Background(), keys...)
+}
+
+// invalidate removes the local copies of the given keys and broadcasts an invalidation so that every other instance
+// removes its local copies too. A failed broadcast is not reported; the other instances catch up once their local
+// copies expire.
+func (t *Tiered) invalidate(ctx context.Context, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	t.evict(keys...)
+	message, err := json.Marshal(tieredInvalidation{
+		Keys:   keys,
+		Origin: t.instanceID,
+	})
+	if err != nil {
+		return
+	}
+	_ = t.l2.Client().Publish(context.WithoutCancel(ctx), t.channel, message).Err()
+}
+
+// listen evicts the local copies named by the invalidations broadcast by other instances until the context is
+// cancelled. The whole local tier is cleared whenever the subscription is (re-)established or fails, since any
+// invalidations sent in the meantime have been missed.
+func (t *Tiered) listen(ctx context.Context) {
+	defer close(t.done)
+	for {
+		received, err := t.pubsub.Receive(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			t.generation.Add(1)
+			t.l1.Clear()
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(tieredResubscribeDelay):
+			}
+			continue
+		}
+		switch message := received.(type) {
+		case *redis.Subscription:
+			t.generation.Add(1)
+			t.l1.Clear()
+		case *redis.Message:
+			invalidation := tieredInvalidation{}
+			if json.Unmarshal([]byte(message.Payload), &invalidation) != nil {
+				t.generation.Add(1)
+				t.l1.Clear()
+				continue
+			}
+			if invalidation.Origin != t.instanceID {
+				t.evict(invalidation.Keys...)
+			}
+		}
+	}
+}
+
+// NewTiered creates and returns a new two-tier cache instance in front of the provided Redis cache along with any error
+// that may have occurred. The two-tier cache takes ownership of the Redis cache, which is closed along with it.
+//
+// The cache starts a background goroutine that listens for invalidations, so Close() should be called once the cache
+// is no longer needed.
+func NewTiered(ctx context.Context, l2 *Redis, connectionArguments *TieredConnectionArguments) (*Tiered, error) {
+	err := ValidateTieredConnectionArguments(l2, connectionArguments)
+	if err != nil {
+		return nil, err
+	}
+	l1, err := NewMemory(&connectionArguments.L1)
+	if err != nil {
+		return nil, err
+	}
+	tiered := &Tiered{
+		channel:    connectionArguments.InvalidationChannel,
+		done:       make(chan struct{}),
+		instanceID: rand.Text(),
+		l1:         l1,
+		l1TTL:      connectionArguments.L1TTL,
+		l2:         l2,
+	}
+	if tiered.channel == "" {
+		tiered.channel = DefaultTieredInvalidationChannel
+	}
+	if tiered.l1TTL == 0 {
+		tiered.l1TTL = DefaultTieredL1TTL
+	}
+
+	// wait for the subscription to be confirmed so that no invalidation sent after this point is missed
+	tiered.pubsub = l2.Client().Subscribe(ctx, tiered.channel)
+	if _, err = tiered.pubsub.Receive(ctx); err != nil {
+		_ = tiered.pubsub.Close()
+		_ = l1.Close()
+		return nil, fmt.Errorf("%w: %w", ErrCannotConnect, err)
+	}
+	listenCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	tiered.cancel = cancel
+	go tiered.listen(listenCtx)
+	return tiered, nil
+}
+
+// ValidateTieredConnectionArguments takes the Redis cache and a TieredConnectionArguments struct pointer and returns an
+// error if any of them are invalid. Returns nil if the validation checks pass.
+func ValidateTieredConnectionArguments(l2 *Redis, connectionArguments *TieredConnectionArguments) error {
+	if l2 == nil || l2.Client() == nil {
+		return ErrCacheCannotBeNil
+	}
+	if connectionArguments == nil {
+		return ErrTieredNoConnectionArguments
+	}
+	if connectionArguments.L1TTL < 0 {
+		return ErrTieredInvalidL1TTL
+	}
+	return ValidateMemoryConnectionArguments(&connectionArguments.L1)
+}
@@ -0,0 +1,193 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Typed wraps a cache so that values of a single type can be stored and retrieved without the caller having to
+// convert them to and from bytes. The conversion is performed by the codec provided at construction time.
+//
+// A value that cannot be decoded is reported through ErrDecodeFailed rather than being treated as a cache miss, so
+// that callers can tell the difference between a missing value and a corrupt (or incompatible) one.
+type Typed[T any] struct {
+	cache Contract
+	codec Codec[T]
+}
+
+// Cache returns the underlying cache.
+func (t *Typed[T]) Cache() Contract {
+	if t == nil {
+		return nil
+	}
+	return t.cache
+}
+
+// Delete removes the item associated with the given key from the cache.
+func (t *Typed[T]) Delete(ctx context.Context, key string) (int64, error) {
+	if t == nil || t.cache == nil {
+		return 0, nil
+	}
+	return t.cache.Delete(ctx, key)
+}
+
+// Exists checks if an item with the given key exists in the cache.
+func (t *Typed[T]) Exists(ctx context.Context, key string) (bool, error) {
+	if t == nil || t.cache == nil {
+		return false, nil
+	}
+	return t.cache.Exists(ctx, key)
+}
+
+// Get retrieves and decodes the item associated with the given key. Returns the value, a boolean describing whether the
+// key was found, and any error that may have occurred.
+func (t *Typed[T]) Get(ctx context.Context, key string) (T, bool, error) {
+	var zero T
+	if t == nil || t.cache == nil {
+		return zero, false, nil
+	}
+	data, err := t.cache.Get(ctx, key)
+	if err != nil || data == nil {
+		return zero, false, err
+	}
+	value, err := t.decode(key, data)
+	if err != nil {
+		return zero, true, err
+	}
+	return value, true, nil
+}
+
+// GetAndDelete atomically retrieves and removes the item associated with the given key, then decodes it. Returns the
+// value, a boolean describing whether the key was found, and any error that may have occurred.
+func (t *Typed[T]) GetAndDelete(ctx context.Context, key string) (T, bool, error) {
+	var zero T
+	if t == nil || t.cache == nil {
+		return zero, false, nil
+	}
+	data, err := t.cache.GetAndDelete(ctx, key)
+	if err != nil || data == nil {
+		return zero, false, err
+	}
+	value, err := t.decode(key, data)
+	if err != nil {
+		return zero, true, err
+	}
+	return value, true, nil
+}
+
+// GetMany retrieves and decodes the items associated with the given keys. Keys that could not be found are omitted
+// from the returned map. If any item cannot be decoded, the items that were decoded are returned alongside the error.
+func (t *Typed[T]) GetMany(ctx context.Context, keys ...string) (map[string]T, error) {
+	values := map[string]T{}
+	if t == nil || t.cache == nil {
+		return values, nil
+	}
+	items, err := t.cache.GetMany(ctx, keys...)
+	if err != nil {
+		return values, err
+	}
+	var decodeErr error
+	for key, data := range items {
+		value, err := t.decode(key, data)
+		if err != nil {
+			decodeErr = err
+			continue
+		}
+		values[key] = value
+	}
+	return values, decodeErr
+}
+
+// Set encodes the value and stores it under the given key.
+func (t *Typed[T]) Set(ctx context.Context, key string, value T) error {
+	if t == nil || t.cache == nil {
+		return nil
+	}
+	data, err := t.encode(key, value)
+	if err != nil {
+		return err
+	}
+	return t.cache.Set(ctx, key, data)
+}
+
+// SetIfNotExists encodes the value and stores it under the given key along with a time-to-live (TTL) duration, but
+// only if the key does not already exist. A zero TTL means the item never expires. Returns whether the value was
+// stored.
+func (t *Typed[T]) SetIfNotExists(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	if t == nil || t.cache == nil {
+		return false, nil
+	}
+	data, err := t.encode(key, value)
+	if err != nil {
+		return false, err
+	}
+	return t.cache.SetIfNotExists(ctx, key, data, ttl)
+}
+
+// SetMany encodes each of the values and stores it under its key along with a time-to-live (TTL) duration. A zero TTL
+// means the items never expire. Nothing is stored if any of the values cannot be encoded.
+func (t *Typed[T]) SetMany(ctx context.Context, values map[string]T, ttl time.Duration) error {
+	if t == nil || t.cache == nil {
+		return nil
+	}
+	items := make(map[string][]byte, len(values))
+	for key, value := range values {
+		data, err := t.encode(key, value)
+		if err != nil {
+			return err
+		}
+		items[key] = data
+	}
+	return t.cache.SetMany(ctx, items, ttl)
+}
+
+// SetWithTTL encodes the value and stores it under the given key along with a time-to-live (TTL) duration.
+func (t *Typed[T]) SetWithTTL(ctx context.Context, key string, value T, ttl time.Duration) error {
+	if t == nil || t.cache == nil {
+		return nil
+	}
+	data, err := t.encode(key, value)
+	if err != nil {
+		return err
+	}
+	return t.cache.SetWithTTL(ctx, key, data, ttl)
+}
+
+// decode converts the bytes stored under the key back into a value.
+func (t *Typed[T]) decode(key string, data []byte) (T, error) {
+	value, err := t.codec.Decode(data)
+	if err != nil {
+		return value, fmt.Errorf("%w: %s: %w", ErrDecodeFailed, key, err)
+	}
+	return value, nil
+}
+
+// encode converts the value to be stored under the key into bytes.
+func (t *Typed[T]) encode(key string, value T) ([]byte, error) {
+	data, err := t.codec.Encode(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrEncodeFailed, key, err)
+	}
+	return data, nil
+}
+
+// NewTyped returns a new typed wrapper around the provided cache that converts values using the given codec. Returns
+// the wrapper plus any error that may have occurred.
+func NewTyped[T any](cache Contract, codec Codec[T]) (*Typed[T], error) {
+	if cache == nil {
+		return nil, ErrCacheCannotBeNil
+	}
+	if codec == nil {
+		return nil, ErrCodecCannotBeNil
+	}
+	return &Typed[T]{
+		cache: cache,
+		codec: codec,
+	}, nil
+}
+
+// NewJSONTyped returns a new typed wrapper around the provided cache that stores values as JSON documents.
+func NewJSONTyped[T any](cache Contract) (*Typed[T], error) {
+	return NewTyped[T](cache, JSONCodec[T]{})
+}
@@ -0,0 +1,196 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+const (
+	// BindTagDefault is the struct tag holding the value used when the property is missing or blank.
+	BindTagDefault string = "default"
+
+	// BindTagProperty is the struct tag holding the name of the property bound to the field. A value of "-" excludes
+	// the field from binding.
+	BindTagProperty string = "config"
+
+	// BindTagRequired is the struct tag describing whether the property must be present with a non-blank value (or a
+	// default) for binding to succeed.
+	BindTagRequired string = "required"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	hostPortType = reflect.TypeOf(HostPort{})
+	urlType      = reflect.TypeOf(url.URL{})
+)
+
+// Bind fills the struct pointed to by target from the configuration using the following struct tags:
+//
+//	config:"DATABASE_HOST" default:"localhost" required:"true"
+//
+// Supported field types are strings, booleans, signed and unsigned integers, floats, time.Duration, url.URL, HostPort,
+// pointers to any of those, and slices of any of those (read as comma-separated lists). Nested and embedded structs
+// without a "config" tag are bound recursively, and nil struct pointers are allocated as needed. Fields without a
+// "config" tag that are not structs are left untouched.
+//
+// Every problem found is collected and returned as a single error wrapping ErrBindFailed. Returns nil if binding
+// succeeds.
+func Bind(cfg Contract, target any) error {
+	targetValue := reflect.ValueOf(target)
+	if !targetValue.IsValid() || targetValue.Kind() != reflect.Pointer || targetValue.IsNil() ||
+		targetValue.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %T", ErrInvalidBindTarget, target)
+	}
+	problems := bindStruct(cfg, targetValue.Elem())
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %w", ErrBindFailed, errors.Join(problems...))
+	}
+	return nil
+}
+
+// bindStruct binds every eligible field of the struct value. Returns the problems encountered.
+func bindStruct(cfg Contract, structValue reflect.Value) []error {
+	problems := []error{}
+	structType := structValue.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fieldValue := structValue.Field(i)
+		property, hasProperty := field.Tag.Lookup(BindTagProperty)
+		if property == "-" {
+			continue
+		}
+		if !hasProperty {
+			problems = append(problems, bindNestedStruct(cfg, fieldValue)...)
+			continue
+		}
+		if err := bindField(cfg, field, fieldValue, PropertyName(property)); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	return problems
+}
+
+// bindNestedStruct binds the field if it is a struct (or a pointer to one) that is not itself a bindable value type.
+// Returns the problems encountered.
+func bindNestedStruct(cfg Contract, fieldValue reflect.Value) []error {
+	fieldType := fieldValue.Type()
+	if fieldType.Kind() == reflect.Pointer && isNestedStructType(fieldType.Elem()) {
+		if fieldValue.IsNil() {
+			fieldValue.Set(reflect.New(fieldType.Elem()))
+		}
+		return bindStruct(cfg, fieldValue.Elem())
+	}
+	if isNestedStructType(fieldType) {
+		return bindStruct(cfg, fieldValue)
+	}
+	return nil
+}
+
+// bindField resolves the property (falling back to the default tag) and stores it in the field. Returns any error
+// that may have occurred.
+func bindField(cfg Contract, field reflect.StructField, fieldValue reflect.Value, property PropertyName) error {
+	value, exists := lookupNonBlankProperty(cfg, property)
+	if !exists {
+		value, exists = field.Tag.Lookup(BindTagDefault)
+	}
+	if !exists {
+		isRequired, _ := strconv.ParseBool(field.Tag.Get(BindTagRequired))
+		if isRequired {
+			return fmt.Errorf("%w: %s", ErrPropertyNotSet, property)
+		}
+		return nil
+	}
+	if err := setFieldFromString(fieldValue, value); err != nil {
+		return fmt.Errorf("%w: %s: field %s: %w", ErrInvalidPropertyValue, property, field.Name, err)
+	}
+	return nil
+}
+
+// isNestedStructType returns whether the type is a struct that should be bound recursively rather than parsed from a
+// single property value.
+func isNestedStructType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != hostPortType && t != urlType && t != reflect.TypeOf(time.Time{})
+}
+
+// setFieldFromString parses the value according to the type of the field and stores the result. Returns any error
+// that may have occurred.
+func setFieldFromString(fieldValue reflect.Value, value string) error {
+	fieldType := fieldValue.Type()
+	switch {
+	case fieldType == durationType:
+		parsed, err := parseDuration(value)
+		if err != nil {
+			return err
+		}
+		fieldValue.SetInt(int64(parsed))
+		return nil
+	case fieldType == hostPortType:
+		parsed, err := parseHostPort(value)
+		if err != nil {
+			return err
+		}
+		fieldValue.Set(reflect.ValueOf(parsed))
+		return nil
+	case fieldType == urlType:
+		parsed, err := parseURL(value)
+		if err != nil {
+			return err
+		}
+		fieldValue.Set(reflect.ValueOf(*parsed))
+		return nil
+	}
+
+	switch fieldType.Kind() {
+	case reflect.String:
+		fieldValue.SetString(value)
+	case reflect.Bool:
+		parsed, err := parseBool(value)
+		if err != nil {
+			return err
+		}
+		fieldValue.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(value, 10, fieldType.Bits())
+		if err != nil {
+			return err
+		}
+		fieldValue.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(value, 10, fieldType.Bits())
+		if err != nil {
+			return err
+		}
+		fieldValue.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(value, fieldType.Bits())
+		if err != nil {
+			return err
+		}
+		fieldValue.SetFloat(parsed)
+	case reflect.Pointer:
+		element := reflect.New(fieldType.Elem())
+		if err := setFieldFromString(element.Elem(), value); err != nil {
+			return err
+		}
+		fieldValue.Set(element)
+	case reflect.Slice:
+		elements, _ := parseList(value)
+		slice := reflect.MakeSlice(fieldType, len(elements), len(elements))
+		for i, element := range elements {
+			if err := setFieldFromString(slice.Index(i), element); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+		fieldValue.Set(slice)
+	default:
+		return fmt.Errorf("unsupported field type %s", fieldType)
+	}
+	return nil
+}
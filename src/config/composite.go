@@ -0,0 +1,477 @@
+package config
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// ConfigurationSourceDefaults is the name of the layer holding the registered property defaults.
+	ConfigurationSourceDefaults string = "defaults"
+
+	// ConfigurationSourceEnvironment is the name of the layer holding the process environment variables.
+	ConfigurationSourceEnvironment string = "environment"
+
+	// ConfigurationSourceFilePrefix is the prefix of the names of the layers holding values read from files. The file
+	// path follows the prefix (e.g., "file:.env").
+	ConfigurationSourceFilePrefix string = "file:"
+
+	// ConfigurationSourceFlags is the name of the layer holding the values set through command-line flags.
+	ConfigurationSourceFlags string = "flags"
+
+	// ConfigurationSourceOverrides is the name of the layer holding values set explicitly through SetProperty.
+	ConfigurationSourceOverrides string = "overrides"
+)
+
+// ConfigurationLayer represents a single named configuration source within a composite configuration.
+type ConfigurationLayer struct {
+	name   string
+	source Contract
+}
+
+// Name returns the name of the layer.
+func (l *ConfigurationLayer) Name() string {
+	if l == nil {
+		return ""
+	}
+	return l.name
+}
+
+// Source returns the configuration source of the layer.
+func (l *ConfigurationLayer) Source() Contract {
+	if l == nil {
+		return nil
+	}
+	return l.source
+}
+
+// NewConfigurationLayer returns a new configuration layer struct instance with the given name and source.
+func NewConfigurationLayer(name string, source Contract) *ConfigurationLayer {
+	return &ConfigurationLayer{
+		name:   name,
+		source: source,
+	}
+}
+
+// CompositeConfig represents an ordered stack of configuration layers. Each property is resolved from the layer with
+// the highest precedence that contains it, and the layer that supplied the value can be queried for debugging.
+//
+// An "overrides" layer is always kept at the top of the stack; SetProperty writes to that layer only, so the
+// underlying sources are never modified.
+//
+// Layers whose sources can be reloaded (see WatchContract) are forwarded to the subscribers of the composite
+// configuration, but only when the change affects the effective value (i.e., it is not shadowed by a layer with a
+// higher precedence).
+type CompositeConfig struct {
+	// layerReloads holds the version assigned to the latest reload of each reloadable layer, keyed by layer index.
+	layerReloads map[int]layerReload
+
+	// layers holds the configuration layers ordered from lowest to highest precedence.
+	layers []*ConfigurationLayer
+
+	// mu guards the reload versions of the layers.
+	mu sync.Mutex
+
+	// notifier manages the change subscriptions of the composite configuration.
+	notifier *changeNotifier
+
+	// overrides holds the values set explicitly through SetProperty.
+	overrides *ConfigurationMap
+
+	// version is incremented once for every layer reload that changes at least one effective value.
+	version atomic.Uint64
+}
+
+// layerReload records the version of the composite configuration that was assigned to a reload of one of its layers.
+type layerReload struct {
+	// layerVersion is the version of the layer produced by the reload.
+	layerVersion uint64
+
+	// version is the version of the composite configuration assigned to the reload.
+	version uint64
+}
+
+// GetAllProperties returns a copy of the map that represents all configuration values after precedence has been
+// applied and references to other properties have been expanded (see InterpolatedConfig).
+func (c *CompositeConfig) GetAllProperties() map[string]string {
+	allConfigProperties := map[string]string{}
+	if c == nil {
+		return allConfigProperties
+	}
+	for _, layer := range c.layers {
+		for configKey, configValue := range layer.Source().GetAllProperties() {
+			allConfigProperties[configKey] = configValue
+		}
+	}
+	return interpolateAllProperties(allConfigProperties, c.getInterpolationProperty)
+}
+
+// GetAllPropertySources returns a map of every property name to the name of the layer that supplied its value.
+func (c *CompositeConfig) GetAllPropertySources() map[string]string {
+	allPropertySources := map[string]string{}
+	if c == nil {
+		return allPropertySources
+	}
+	for _, layer := range c.layers {
+		for configKey := range layer.Source().GetAllProperties() {
+			allPropertySources[configKey] = layer.Name()
+		}
+	}
+	return allPropertySources
+}
+
+// GetLayerNames returns the names of all layers ordered from lowest to highest precedence.
+func (c *CompositeConfig) GetLayerNames() []string {
+	layerNames := []string{}
+	if c == nil {
+		return layerNames
+	}
+	for _, layer := range c.layers {
+		layerNames = append(layerNames, layer.Name())
+	}
+	return layerNames
+}
+
+// GetInterpolatedProperty takes a property name and returns the matching value with its references expanded, a
+// boolean describing whether the property name actually exists, and any error that occurred during the expansion.
+func (c *CompositeConfig) GetInterpolatedProperty(property PropertyName) (string, bool, error) {
+	return interpolateProperty(property, c.getInterpolationProperty)
+}
+
+// GetProperty takes a property name and returns the matching string value from the layer with the highest precedence
+// that contains it, plus a boolean describing whether the property name actually exists and was therefore valid.
+// References to other properties are expanded across every layer (see InterpolatedConfig); values that cannot be
+// expanded are returned as-is.
+func (c *CompositeConfig) GetProperty(property PropertyName) (string, bool) {
+	value, exists, err := interpolateProperty(property, c.getInterpolationProperty)
+	if err != nil {
+		return c.getRawProperty(property)
+	}
+	return value, exists
+}
+
+// GetPropertySource returns the name of the layer that supplied the value of the property plus a boolean describing
+// whether the property name actually exists and was therefore valid.
+func (c *CompositeConfig) GetPropertySource(property PropertyName) (string, bool) {
+	layer := c.findLayer(property)
+	if layer == nil {
+		return "", false
+	}
+	return layer.Name(), true
+}
+
+// HasProperty returns whether the property name exists within any layer of the configuration.
+func (c *CompositeConfig) HasProperty(property PropertyName) bool {
+	return c.findLayer(property) != nil
+}
+
+// SetProperty sets the property with the given name to the provided value within the overrides layer. Returns a
+// boolean describing whether the property was already present in any layer and was therefore overwritten.
+func (c *CompositeConfig) SetProperty(property PropertyName, value string) bool {
+	if c == nil || c.overrides == nil {
+		return false
+	}
+	willBeOverwritten := c.HasProperty(property)
+	c.overrides.SetProperty(property, value)
+	return willBeOverwritten
+}
+
+// Subscribe registers a handler function that is invoked whenever the effective value of the given property changes
+// due to a reload. The values reported in each change are taken before references are expanded. Returns a function
+// that removes the subscription.
+func (c *CompositeConfig) Subscribe(property PropertyName, handler ChangeHandlerFunc) func() {
+	if c == nil || property == "" {
+		return func() {}
+	}
+	return c.notifier.subscribe(property, handler)
+}
+
+// SubscribeAll registers a handler function that is invoked whenever the effective value of any property changes due
+// to a reload. Returns a function that removes the subscription.
+func (c *CompositeConfig) SubscribeAll(handler ChangeHandlerFunc) func() {
+	if c == nil {
+		return func() {}
+	}
+	return c.notifier.subscribe("", handler)
+}
+
+// ValidateInterpolation attempts to expand every property. Every problem found (e.g., a reference cycle) is collected
+// and returned as a single error. Returns nil if every property can be expanded.
+func (c *CompositeConfig) ValidateInterpolation() error {
+	if c == nil {
+		return nil
+	}
+	allConfigProperties := map[string]string{}
+	for _, layer := range c.layers {
+		for configKey, configValue := range layer.Source().GetAllProperties() {
+			allConfigProperties[configKey] = configValue
+		}
+	}
+	return validateInterpolation(allConfigProperties, c.getInterpolationProperty)
+}
+
+// Version returns a counter that is incremented every time a reload of a layer changes the effective value of at least
+// one property. Every change produced by the same reload carries the same version.
+func (c *CompositeConfig) Version() uint64 {
+	if c == nil {
+		return 0
+	}
+	return c.version.Load()
+}
+
+// Watch watches every layer whose source can be reloaded (see WatchContract) at the given interval. Reload failures
+// are passed to the error handler (if any) and leave the current values in place.
+//
+// This method BLOCKS until ctx.Done() is closed, so it should be run in its own goroutine.
+func (c *CompositeConfig) Watch(ctx context.Context, interval time.Duration, onReloadError func(error)) error {
+	if c == nil {
+		return ErrConfigurationCannotBeNil
+	}
+	if interval <= 0 {
+		return ErrInvalidWatchInterval
+	}
+	var wg sync.WaitGroup
+	for _, layer := range c.layers {
+		if watchable, ok := layer.Source().(WatchContract); ok {
+			wg.Add(1)
+			go func(source WatchContract) {
+				defer wg.Done()
+				_ = source.Watch(ctx, interval, onReloadError)
+			}(watchable)
+		}
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// findLayer returns the layer with the highest precedence that contains the property, or nil if no layer does.
+func (c *CompositeConfig) findLayer(property PropertyName) *ConfigurationLayer {
+	index := c.findLayerIndex(property, len(c.getLayers()))
+	if index < 0 {
+		return nil
+	}
+	return c.layers[index]
+}
+
+// findLayerIndex returns the index of the layer with the highest precedence below the given index that contains the
+// property, or -1 if no such layer does.
+func (c *CompositeConfig) findLayerIndex(property PropertyName, belowIndex int) int {
+	layers := c.getLayers()
+	for i := min(belowIndex, len(layers)) - 1; i >= 0; i-- {
+		if layers[i].Source() != nil && layers[i].Source().HasProperty(property) {
+			return i
+		}
+	}
+	return -1
+}
+
+// forwardLayerChange notifies the subscribers of the composite configuration about a change that occurred within the
+// layer at the given index, provided that the change affects the effective value of the property.
+func (c *CompositeConfig) forwardLayerChange(layerIndex int, change PropertyChange) {
+	if c.findLayerIndex(change.Property, len(c.layers)) > layerIndex {
+		// the change is shadowed by a layer with a higher precedence
+		return
+	}
+	newValue, newExists := c.getRawProperty(change.Property)
+	oldValue, oldExists := change.OldValue, change.OldExists
+	if !oldExists {
+		if index := c.findLayerIndex(change.Property, layerIndex); index >= 0 {
+			oldValue, oldExists = c.layers[index].Source().GetProperty(change.Property)
+		}
+	}
+	if oldExists == newExists && oldValue == newValue {
+		return
+	}
+	c.notifier.notify([]PropertyChange{
+		{
+			NewExists: newExists,
+			NewValue:  newValue,
+			OldExists: oldExists,
+			OldValue:  oldValue,
+			Property:  change.Property,
+			Version:   c.getReloadVersion(layerIndex, change.Version),
+		},
+	})
+}
+
+// getReloadVersion returns the version of the composite configuration for a change produced by the layer at the given
+// index with the given layer version. The first forwarded change of each reload increments the version and the rest of
+// the changes of that reload share it. Changes without a layer version always increment the version.
+func (c *CompositeConfig) getReloadVersion(layerIndex int, layerVersion uint64) uint64 {
+	// ensure we don't get a collision if two or more layers are reloaded concurrently
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if reload, exists := c.layerReloads[layerIndex]; exists && layerVersion != 0 && reload.layerVersion == layerVersion {
+		return reload.version
+	}
+	if c.layerReloads == nil {
+		c.layerReloads = map[int]layerReload{}
+	}
+	version := c.version.Add(1)
+	c.layerReloads[layerIndex] = layerReload{
+		layerVersion: layerVersion,
+		version:      version,
+	}
+	return version
+}
+
+// getInterpolationProperty returns the value of the property in the same way as getRawProperty, except that the
+// references within literal values are escaped so that they are never expanded.
+func (c *CompositeConfig) getInterpolationProperty(property PropertyName) (string, bool) {
+	layer := c.findLayer(property)
+	if layer == nil {
+		return "", false
+	}
+	return getInterpolationLookup(layer.Source())(property)
+}
+
+// getRawProperty returns the value of the property from the layer with the highest precedence that contains it,
+// without expanding its references, plus a boolean describing whether the property exists.
+func (c *CompositeConfig) getRawProperty(property PropertyName) (string, bool) {
+	layer := c.findLayer(property)
+	if layer == nil {
+		return "", false
+	}
+	return layer.Source().GetProperty(property)
+}
+
+// getLayers returns the layers of the composite configuration, or nil if the configuration itself is nil.
+func (c *CompositeConfig) getLayers() []*ConfigurationLayer {
+	if c == nil {
+		return nil
+	}
+	return c.layers
+}
+
+// makeLayerReloadValidator returns a reload validator for the layer at the given index that validates the candidate
+// values merged with every other layer against the provided schema registry.
+func (c *CompositeConfig) makeLayerReloadValidator(layerIndex int, registry *SchemaRegistry) ReloadValidatorFunc {
+	return func(candidate Contract) error {
+		layers := slices.Clone(c.layers)
+		layers[layerIndex] = NewConfigurationLayer(layers[layerIndex].Name(), candidate)
+		return registry.Validate(&CompositeConfig{
+			layers:    layers,
+			overrides: c.overrides,
+		})
+	}
+}
+
+// NewCompositeConfiguration returns a new composite configuration struct instance built from the provided layers,
+// which must be ordered from lowest to highest precedence. An empty overrides layer is added on top automatically.
+func NewCompositeConfiguration(layers ...*ConfigurationLayer) *CompositeConfig {
+	overrides := NewConfigurationMap()
+	allLayers := []*ConfigurationLayer{}
+	for _, layer := range layers {
+		if layer != nil && layer.Source() != nil {
+			allLayers = append(allLayers, layer)
+		}
+	}
+	allLayers = append(allLayers, NewConfigurationLayer(ConfigurationSourceOverrides, overrides))
+	config := &CompositeConfig{
+		layers:    allLayers,
+		notifier:  newChangeNotifier(),
+		overrides: overrides,
+	}
+	for i, layer := range config.layers {
+		if watchable, ok := layer.Source().(WatchContract); ok {
+			watchable.SubscribeAll(func(change PropertyChange) {
+				config.forwardLayerChange(i, change)
+			})
+		}
+	}
+	return config
+}
+
+// LoadCompositeConfiguration loads a composite configuration from the registered property defaults, the default
+// configuration file, and the process environment. Returns the config struct instance plus any error that may have
+// occurred.
+func LoadCompositeConfiguration() (*CompositeConfig, error) {
+	return LoadCompositeConfigurationFromFiles(DefaultConfigurationFilePath)
+}
+
+// LoadCompositeConfigurationFromFiles loads a composite configuration from the registered property defaults, the
+// provided files, and the process environment. See LoadCompositeConfigurationWithArguments for details. Returns the
+// config struct instance plus any error that may have occurred.
+func LoadCompositeConfigurationFromFiles(paths ...string) (*CompositeConfig, error) {
+	return LoadCompositeConfigurationWithArguments(nil, paths...)
+}
+
+// LoadCompositeConfigurationWithArguments loads a composite configuration from the following layers, ordered from
+// lowest to highest precedence:
+//
+//  1. the registered property defaults
+//  2. each of the provided files, in order, each followed by its overlay files (see GetOverlayFilePaths); every file
+//     becomes its own layer, named after its path, and missing files are skipped except for overlay files, which
+//     become empty layers so that Watch picks them up once they have been created. Overlay files are loaded whether
+//     or not the file they belong to exists
+//  3. the process environment
+//  4. the command-line flags parsed from the arguments (see ParseFlagConfiguration), if there are any
+//  5. explicit overrides set through SetProperty
+//
+// Files are skipped entirely if LOAD_ENV_FROM_FILE is explicitly set to false in the process environment or through a
+// flag. The merged result is validated against the default schema registry. Returns the config struct instance plus
+// any error that may have occurred.
+func LoadCompositeConfigurationWithArguments(args []string, paths ...string) (*CompositeConfig, error) {
+	environmentConfig := readEnvironmentConfiguration()
+	flagConfig, err := ParseFlagConfiguration(args)
+	if err != nil {
+		return nil, err
+	}
+	shouldLoadFromFile, err := GetPropertyAsBoolWithDefault(NewCompositeConfiguration(
+		NewConfigurationLayer(ConfigurationSourceEnvironment, environmentConfig),
+		NewConfigurationLayer(ConfigurationSourceFlags, flagConfig),
+	), PropertyNameLoadEnvFromFile, true)
+	if err != nil {
+		return nil, err
+	}
+
+	layers := []*ConfigurationLayer{
+		NewConfigurationLayer(ConfigurationSourceDefaults, makeDefaultsConfiguration(DefaultSchemaRegistry())),
+	}
+	if shouldLoadFromFile {
+		for _, path := range paths {
+			fileConfigs, err := readOverlayFileConfigurations(path, false)
+			if err != nil {
+				return nil, err
+			}
+			for _, fileConfig := range fileConfigs {
+				layers = append(layers, NewConfigurationLayer(
+					ConfigurationSourceFilePrefix+strings.Join(fileConfig.sourcePaths, ","), fileConfig,
+				))
+			}
+		}
+	}
+	layers = append(layers, NewConfigurationLayer(ConfigurationSourceEnvironment, environmentConfig))
+	if len(args) > 0 {
+		layers = append(layers, NewConfigurationLayer(ConfigurationSourceFlags, flagConfig))
+	}
+
+	config := NewCompositeConfiguration(layers...)
+	if err := DefaultSchemaRegistry().Validate(config); err != nil {
+		return nil, err
+	}
+
+	// reloaded file layers are validated together with every other layer rather than on their own
+	for i, layer := range config.layers {
+		if fileConfig, ok := layer.Source().(*FileBasedConfig); ok {
+			fileConfig.SetReloadValidator(config.makeLayerReloadValidator(i, DefaultSchemaRegistry()))
+		}
+	}
+	return config, nil
+}
+
+// makeDefaultsConfiguration returns a configuration map holding the default value of every property registered within
+// the schema registry that has one.
+func makeDefaultsConfiguration(registry *SchemaRegistry) *ConfigurationMap {
+	defaults := NewConfigurationMap()
+	for _, schema := range registry.GetSchemas() {
+		if schema.Default != "" {
+			defaults.SetProperty(schema.Name, schema.Default)
+		}
+	}
+	return defaults
+}
@@ -51,9 +51,26 @@ func NewEnvironmentConfiguration() *EnvironmentBasedConfig {
 	}
 }
 
-// LoadEnvironmentConfiguration loads the environment configuration from the current environment variables. Returns the
-// config struct instance plus any error that may have occurred.
+// LoadEnvironmentConfiguration loads the environment configuration from the current environment variables, expands
+// the references within the values against the process environment, applies the registered property defaults, and
+// validates the result against the default schema registry. Returns the config struct instance plus any error that
+// may have occurred.
 func LoadEnvironmentConfiguration() (*EnvironmentBasedConfig, error) {
+	config := readEnvironmentConfiguration()
+	expanded, err := expandProperties(config.GetAllProperties(), nil)
+	if err != nil {
+		return nil, err
+	}
+	config.configuration.ReplaceAllProperties(expanded)
+	if err := applyDefaultsAndValidate(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// readEnvironmentConfiguration reads every available configuration key from the current environment variables without
+// applying defaults or performing validation. Returns the config struct instance.
+func readEnvironmentConfiguration() *EnvironmentBasedConfig {
 	configMap := map[string]string{}
 
 	availableKeys := GetAvailableConfigurationKeys()
@@ -64,8 +81,7 @@ func LoadEnvironmentConfiguration() (*EnvironmentBasedConfig, error) {
 		}
 	}
 
-	config := &EnvironmentBasedConfig{
+	return &EnvironmentBasedConfig{
 		configuration: NewConfigurationMapFromMap(configMap),
 	}
-	return config, nil
 }
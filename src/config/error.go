@@ -5,3 +5,89 @@ import "errors"
 // ErrFailedLoadingConfigurationFile is a sentinel error representing a situation where the configuration file could
 // not be loaded for some reason.
 var ErrFailedLoadingConfigurationFile = errors.New("failed loading configuration file")
+
+// ErrInvalidPropertyValue is a sentinel error representing a configuration property value that could not be parsed
+// into the requested type.
+var ErrInvalidPropertyValue = errors.New("invalid configuration property value")
+
+// ErrPropertyNotSet is a sentinel error representing a configuration property that is either missing or blank.
+var ErrPropertyNotSet = errors.New("configuration property is not set")
+
+// ErrConfigurationValidationFailed is a sentinel error representing a configuration that failed one or more schema
+// validation checks.
+var ErrConfigurationValidationFailed = errors.New("configuration validation failed")
+
+// ErrInvalidPropertySchema is a sentinel error representing an attempt to register an invalid property schema.
+var ErrInvalidPropertySchema = errors.New("invalid property schema")
+
+// ErrPropertyAlreadyRegistered is a sentinel error representing an attempt to register a property name that has
+// already been registered.
+var ErrPropertyAlreadyRegistered = errors.New("configuration property is already registered")
+
+// ErrPropertyValueNotAllowed is a sentinel error representing a configuration property value that is not one of the
+// allowed values for that property.
+var ErrPropertyValueNotAllowed = errors.New("configuration property value is not allowed")
+
+// ErrSchemaRegistryCannotBeNil is a sentinel error representing an attempt to use a nil schema registry.
+var ErrSchemaRegistryCannotBeNil = errors.New("schema registry instance cannot be nil")
+
+// ErrConfigurationKeyCollision is a sentinel error representing two different keys within a structured configuration
+// file that map onto the same property name once flattened.
+var ErrConfigurationKeyCollision = errors.New("configuration keys collide after flattening")
+
+// ErrInvalidConfigurationFileFormat is a sentinel error representing a configuration file whose contents could not be
+// decoded in the format implied by its extension.
+var ErrInvalidConfigurationFileFormat = errors.New("invalid configuration file format")
+
+// ErrBindFailed is a sentinel error representing a failure to bind one or more configuration properties to the
+// fields of a struct.
+var ErrBindFailed = errors.New("cannot bind configuration to struct")
+
+// ErrInvalidBindTarget is a sentinel error representing an attempt to bind configuration to something other than a
+// non-nil pointer to a struct.
+var ErrInvalidBindTarget = errors.New("bind target must be a non-nil pointer to a struct")
+
+// ErrConfigurationCannotBeNil is a sentinel error representing an attempt to use a nil configuration.
+var ErrConfigurationCannotBeNil = errors.New("configuration instance cannot be nil")
+
+// ErrConfigurationReloadRejected is a sentinel error representing a configuration reload that was rejected because the
+// sources could not be read or the result failed validation. The previous values remain in place.
+var ErrConfigurationReloadRejected = errors.New("configuration reload rejected")
+
+// ErrInvalidWatchInterval is a sentinel error representing an attempt to watch configuration sources with a
+// non-positive polling interval.
+var ErrInvalidWatchInterval = errors.New("configuration watch interval must be positive")
+
+// ErrCannotResolveSecret is a sentinel error representing a failure while resolving a secret from one of its sources.
+var ErrCannotResolveSecret = errors.New("cannot resolve secret")
+
+// ErrInvalidSecretPropertyPair is a sentinel error representing an attempt to register an incomplete secret property
+// pair.
+var ErrInvalidSecretPropertyPair = errors.New("invalid secret property pair")
+
+// ErrInvalidSecretStoreResponse is a sentinel error representing a secret store response that could not be decoded.
+var ErrInvalidSecretStoreResponse = errors.New("invalid secret store response")
+
+// ErrInvalidSecretStoreURL is a sentinel error representing a secret store base URL that could not be parsed.
+var ErrInvalidSecretStoreURL = errors.New("invalid secret store url")
+
+// ErrSecretResolverCannotBeNil is a sentinel error representing an attempt to use a nil secret resolver.
+var ErrSecretResolverCannotBeNil = errors.New("secret resolver instance cannot be nil")
+
+// ErrSecretStoreRequestFailed is a sentinel error representing a failed request to a secret store.
+var ErrSecretStoreRequestFailed = errors.New("secret store request failed")
+
+// ErrInterpolationCycle is a sentinel error representing configuration properties whose references to each other
+// form a cycle and therefore cannot be expanded.
+var ErrInterpolationCycle = errors.New("configuration property references form a cycle")
+
+// ErrInvalidInterpolation is a sentinel error representing a malformed reference to another configuration property.
+var ErrInvalidInterpolation = errors.New("invalid configuration property reference")
+
+// ErrConfigurationFlagMissingValue is a sentinel error representing a command-line flag that requires a value but was
+// given none.
+var ErrConfigurationFlagMissingValue = errors.New("configuration flag is missing a value")
+
+// ErrUnknownConfigurationFlag is a sentinel error representing a command-line flag that does not map onto a
+// registered configuration property.
+var ErrUnknownConfigurationFlag = errors.New("unknown configuration flag")
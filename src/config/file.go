@@ -1,17 +1,48 @@
 package config
 
 import (
+	"context"
+	"errors"
 	"fmt"
-
-	"github.com/joho/godotenv"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
+// DefaultConfigurationFilePath is the path of the configuration file that is loaded when no path is specified.
+const DefaultConfigurationFilePath string = ".env"
+
 // FileBasedConfig represents a set of mapped configuration values. It also contains a mutex so it should ONLY be
 // passed around by-reference and never by-value.
 //
-// This configuration implementation is intended to load values from a file (e.g., .env file).
+// This configuration implementation is intended to load values from a file (e.g., .env file). Files with a ".json",
+// ".toml", ".yaml", or ".yml" extension are decoded in that format and flattened as described by
+// FlattenConfigurationValues; every other file is read as a dotenv file.
+//
+// File-based configurations can be reloaded while the service is running (see Reload and Watch). A reload replaces
+// all values atomically, increments the version counter, and notifies any subscribers about the properties that
+// changed. A reload that fails validation is rejected and leaves the current values in place.
+//
+// Single-quoted dotenv values are literal, so their "${...}" references are never expanded (see InterpolatedConfig).
 type FileBasedConfig struct {
-	configuration *ConfigurationMap
+	configuration   *ConfigurationMap
+	files           []string
+	isExpanded      bool
+	isOptional      bool
+	literals        map[string]bool
+	mu              sync.Mutex
+	notifier        *changeNotifier
+	overlayBasePath string
+	reloadMu        sync.Mutex
+	reloadValidator ReloadValidatorFunc
+	sourcePaths     []string
+	version         atomic.Uint64
 }
 
 // GetAllProperties returns a copy of the map that represents all configuration values.
@@ -22,6 +53,18 @@ func (c *FileBasedConfig) GetAllProperties() map[string]string {
 	return c.configuration.GetAllProperties()
 }
 
+// GetLoadedFiles returns the paths of the files that were actually read, in the order in which they were applied.
+func (c *FileBasedConfig) GetLoadedFiles() []string {
+	if c == nil {
+		return []string{}
+	}
+
+	// ensure we don't get a collision if a reload happens concurrently
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.files)
+}
+
 // GetProperty takes a property name and returns the matching string value from the configuration plus a boolean
 // describing whether the property name actually exists and was therefore valid.
 func (c *FileBasedConfig) GetProperty(property PropertyName) (string, bool) {
@@ -39,19 +82,208 @@ func (c *FileBasedConfig) HasProperty(property PropertyName) bool {
 	return c.configuration.HasProperty(property)
 }
 
-// SetProperty sets the property with the given name to the provided value. Returns a boolean describing whether the
-// property was already present and was therefore overwritten.
+// SetProperty sets the property with the given name to the provided value. The value is never literal, even if it
+// replaces a single-quoted value. Returns a boolean describing whether the property was already present and was
+// therefore overwritten.
 func (c *FileBasedConfig) SetProperty(property PropertyName, value string) bool {
 	if c == nil || c.configuration == nil {
 		return false
 	}
+
+	// ensure we don't get a collision if two or more goroutines try to write concurrently
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.literals, string(property))
 	return c.configuration.SetProperty(property, value)
 }
 
+// Reload re-reads the source files of the configuration and, if the result passes validation, atomically replaces
+// the current values with it. Subscribers are notified about every property that changed. Returns an error wrapping
+// ErrConfigurationReloadRejected if the files cannot be read or the result fails validation, in which case the current
+// values are left in place.
+//
+// Unless a custom validator has been set with SetReloadValidator, the reloaded values have the registered property
+// defaults applied and are validated against the default schema registry. The references within the reloaded values
+// are expanded first if the configuration was loaded with them expanded (see LoadFileConfigurationFromFile).
+func (c *FileBasedConfig) Reload() error {
+	if c == nil || c.configuration == nil {
+		return nil
+	}
+
+	// ensure two or more reloads never interleave
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	var candidate *FileBasedConfig
+	var err error
+	switch {
+	case c.overlayBasePath != "":
+		candidate, err = readFileConfigurationWithOverlays(c.overlayBasePath)
+	case c.isOptional && len(c.sourcePaths) == 1:
+		candidate, err = readOptionalFileConfiguration(c.sourcePaths[0])
+	case len(c.sourcePaths) > 0:
+		candidate, err = readFileConfiguration(c.sourcePaths...)
+	default:
+		return nil
+	}
+	if err == nil && c.isExpanded {
+		err = candidate.expandReferences()
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrConfigurationReloadRejected, err)
+	}
+	c.mu.Lock()
+	validate := c.reloadValidator
+	c.mu.Unlock()
+	if validate == nil {
+		validate = applyDefaultsAndValidate
+	}
+	if err := validate(candidate); err != nil {
+		return fmt.Errorf("%w: %w", ErrConfigurationReloadRejected, err)
+	}
+
+	c.mu.Lock()
+	changes := c.configuration.ReplaceAllProperties(candidate.GetAllProperties())
+	c.files = candidate.GetLoadedFiles()
+	c.literals = candidate.getLiteralProperties()
+	c.mu.Unlock()
+	if len(changes) == 0 {
+		return nil
+	}
+	version := c.version.Add(1)
+	for i := range changes {
+		changes[i].Version = version
+	}
+	c.notifier.notify(changes)
+	return nil
+}
+
+// SetReloadValidator replaces the function used to decide whether reloaded values may replace the current values. A
+// nil validator restores the default behaviour of applying the registered property defaults and validating against
+// the default schema registry.
+func (c *FileBasedConfig) SetReloadValidator(validator ReloadValidatorFunc) {
+	if c == nil {
+		return
+	}
+
+	// ensure we don't get a collision if two or more goroutines try to write concurrently
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reloadValidator = validator
+}
+
+// Subscribe registers a handler function that is invoked whenever the given property changes during a reload. Returns
+// a function that removes the subscription.
+func (c *FileBasedConfig) Subscribe(property PropertyName, handler ChangeHandlerFunc) func() {
+	if c == nil || property == "" {
+		return func() {}
+	}
+	return c.notifier.subscribe(property, handler)
+}
+
+// SubscribeAll registers a handler function that is invoked whenever any property changes during a reload. Returns a
+// function that removes the subscription.
+func (c *FileBasedConfig) SubscribeAll(handler ChangeHandlerFunc) func() {
+	if c == nil {
+		return func() {}
+	}
+	return c.notifier.subscribe("", handler)
+}
+
+// Version returns a counter that is incremented every time a reload changes at least one property.
+func (c *FileBasedConfig) Version() uint64 {
+	if c == nil {
+		return 0
+	}
+	return c.version.Load()
+}
+
+// Watch polls the source files of the configuration at the given interval and reloads them whenever any of them is
+// created, removed, or modified. Reload failures are passed to the error handler (if any) and leave the current values
+// in place.
+//
+// This method BLOCKS until ctx.Done() is closed, so it should be run in its own goroutine.
+func (c *FileBasedConfig) Watch(ctx context.Context, interval time.Duration, onReloadError func(error)) error {
+	if c == nil {
+		return ErrConfigurationCannotBeNil
+	}
+	return watchFiles(ctx, interval, c.getWatchedPaths, func() {
+		if err := c.Reload(); err != nil && onReloadError != nil {
+			onReloadError(err)
+		}
+	})
+}
+
+// expandReferences expands the references within every value in place, looking up the referenced properties within the
+// configuration first and within the process environment second (see expandProperties). Every value is literal
+// afterwards so that it is never expanded again. Returns an error if any of the values cannot be expanded.
+func (c *FileBasedConfig) expandReferences() error {
+	expanded, err := expandProperties(c.GetAllProperties(), c.isLiteralProperty)
+	if err != nil {
+		return err
+	}
+	literals := map[string]bool{}
+	for name, value := range expanded {
+		if strings.Contains(value, "${") {
+			literals[name] = true
+		}
+	}
+
+	// ensure we don't get a collision if two or more goroutines try to write concurrently
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.configuration.ReplaceAllProperties(expanded)
+	c.literals = literals
+	c.isExpanded = true
+	return nil
+}
+
+// getLiteralProperties returns a copy of the set of properties whose values are literal.
+func (c *FileBasedConfig) getLiteralProperties() map[string]bool {
+	// ensure we don't get a collision if a reload happens concurrently
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return maps.Clone(c.literals)
+}
+
+// getWatchedPaths returns the paths of every file that may contribute to the configuration, whether or not it exists.
+func (c *FileBasedConfig) getWatchedPaths() []string {
+	if c.overlayBasePath != "" {
+		baseConfig, err := readFileConfiguration(c.overlayBasePath)
+		if err != nil {
+			return []string{c.overlayBasePath}
+		}
+		return GetOverlayFilePaths(c.overlayBasePath, resolveOverlayEnvironment(baseConfig))
+	}
+	return slices.Clone(c.sourcePaths)
+}
+
+// isLiteralProperty returns whether the value of the property is literal (i.e., it was single-quoted within a dotenv
+// file) and its references must therefore never be expanded.
+func (c *FileBasedConfig) isLiteralProperty(property PropertyName) bool {
+	if c == nil {
+		return false
+	}
+
+	// ensure we don't get a collision if a reload happens concurrently
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.literals[string(property)]
+}
+
 // NewFileConfiguration returns a new empty file configuration struct instance.
 func NewFileConfiguration() *FileBasedConfig {
+	return newFileBasedConfig(map[string]string{}, []string{})
+}
+
+// newFileBasedConfig returns a new file configuration struct instance holding the provided values and recording the
+// provided files as having been read.
+func newFileBasedConfig(configMap map[string]string, files []string) *FileBasedConfig {
 	return &FileBasedConfig{
-		configuration: NewConfigurationMap(),
+		configuration: NewConfigurationMapFromMap(configMap),
+		files:         slices.Clone(files),
+		literals:      map[string]bool{},
+		notifier:      newChangeNotifier(),
 	}
 }
 
@@ -61,24 +293,161 @@ func LoadFileConfiguration() (*FileBasedConfig, error) {
 	return LoadFileConfigurationFromFile(nil)
 }
 
-// LoadFileConfigurationFromFile loads the environment configuration from the specified file or from the default path
-// if the path is nil. Returns the config struct instance plus any error that may have occurred.
+// LoadFileConfigurationFromFile loads the environment configuration from the specified file (or from the default path
+// if the path is nil) followed by its overlay files as described by GetOverlayFilePaths, expands the references within
+// the values against the files and the process environment, applies the registered property defaults, and validates
+// the result against the default schema registry. Missing overlay files are skipped. Returns the config struct
+// instance plus any error that may have occurred.
 func LoadFileConfigurationFromFile(path *string) (*FileBasedConfig, error) {
-	var err error
-	configMap := map[string]string{}
-
-	configFilenames := []string{}
+	basePath := DefaultConfigurationFilePath
 	if path != nil {
-		configFilenames = append(configFilenames, *path)
+		basePath = *path
 	}
 
-	configMap, err = godotenv.Read(configFilenames...)
+	config, err := readFileConfigurationWithOverlays(basePath)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %w", ErrFailedLoadingConfigurationFile, err)
+		return nil, err
+	}
+	if err := config.expandReferences(); err != nil {
+		return nil, err
 	}
+	if err := applyDefaultsAndValidate(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
 
-	config := &FileBasedConfig{
-		configuration: NewConfigurationMapFromMap(configMap),
+// GetOverlayFilePaths takes the path of a base configuration file plus an environment name and returns the paths of
+// the files to load in order of increasing precedence: the base file, the environment-specific overlay (omitted when
+// the environment is blank), and the local overlay. For dotenv files the suffix is appended to the path (e.g., ".env"
+// and "dev" produce ".env", ".env.dev", and ".env.local"), whereas for structured formats it is inserted before the
+// extension (e.g., "config.yaml" and "dev" produce "config.yaml", "config.dev.yaml", and "config.local.yaml").
+func GetOverlayFilePaths(basePath string, environment string) []string {
+	makeOverlayPath := func(suffix string) string {
+		return fmt.Sprintf("%s.%s", basePath, suffix)
+	}
+	if GetConfigurationFileFormat(basePath).IsStructured() {
+		extension := filepath.Ext(basePath)
+		makeOverlayPath = func(suffix string) string {
+			return fmt.Sprintf("%s.%s%s", strings.TrimSuffix(basePath, extension), suffix, extension)
+		}
 	}
+	paths := []string{basePath}
+	if environment = strings.TrimSpace(environment); environment != "" {
+		paths = append(paths, makeOverlayPath(environment))
+	}
+	return append(paths, makeOverlayPath("local"))
+}
+
+// readFileConfiguration reads the specified files (or the default path if none are specified) without applying
+// defaults or performing validation. Later files override values from earlier ones. Returns the config struct instance
+// plus any error that may have occurred.
+func readFileConfiguration(paths ...string) (*FileBasedConfig, error) {
+	if len(paths) == 0 {
+		paths = []string{DefaultConfigurationFilePath}
+	}
+	configMap := map[string]string{}
+	literals := map[string]bool{}
+	for _, path := range paths {
+		fileMap, fileLiterals, err := readConfigurationFileMap(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrFailedLoadingConfigurationFile, err)
+		}
+		mergeConfigurationValues(configMap, literals, fileMap, fileLiterals)
+	}
+
+	config := newFileBasedConfig(configMap, paths)
+	config.literals = literals
+	config.sourcePaths = slices.Clone(paths)
 	return config, nil
 }
+
+// mergeConfigurationValues copies the values of a file over the values read so far, keeping track of which of the
+// merged values are literal.
+func mergeConfigurationValues(
+	configMap map[string]string, literals map[string]bool, fileMap map[string]string, fileLiterals []string,
+) {
+	for name, value := range fileMap {
+		configMap[name] = value
+		delete(literals, name)
+	}
+	for _, name := range fileLiterals {
+		literals[name] = true
+	}
+}
+
+// readOptionalFileConfiguration reads the specified file in the same way as readFileConfiguration, except that a
+// missing file produces an empty configuration. The file stays optional when the configuration is reloaded, so it is
+// picked up once it has been created. Returns the config struct instance plus any error that may have occurred.
+func readOptionalFileConfiguration(path string) (*FileBasedConfig, error) {
+	config, err := readFileConfiguration(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		config = newFileBasedConfig(map[string]string{}, []string{})
+		config.sourcePaths = []string{path}
+	} else if err != nil {
+		return nil, err
+	}
+	config.isOptional = true
+	return config, nil
+}
+
+// readFileConfigurationWithOverlays reads the base file and its overlay files, merging them into a single
+// configuration without applying defaults or performing validation. Returns the config struct instance plus any error
+// that may have occurred.
+func readFileConfigurationWithOverlays(basePath string) (*FileBasedConfig, error) {
+	fileConfigs, err := readOverlayFileConfigurations(basePath, true)
+	if err != nil {
+		return nil, err
+	}
+	configMap := map[string]string{}
+	literals := map[string]bool{}
+	files := []string{}
+	for _, fileConfig := range fileConfigs {
+		fileLiterals := slices.Collect(maps.Keys(fileConfig.getLiteralProperties()))
+		mergeConfigurationValues(configMap, literals, fileConfig.GetAllProperties(), fileLiterals)
+		files = append(files, fileConfig.GetLoadedFiles()...)
+	}
+
+	config := newFileBasedConfig(configMap, files)
+	config.literals = literals
+	config.overlayBasePath = basePath
+	return config, nil
+}
+
+// readOverlayFileConfigurations reads the base file and each of its overlay files into separate configurations ordered
+// by increasing precedence. The base file must exist if it is required and is otherwise left out when it is missing,
+// in which case only the process environment selects the overlay. Every overlay file is optional and produces an empty
+// configuration while it is missing (see readOptionalFileConfiguration). Returns the config struct instances plus any
+// error that may have occurred.
+func readOverlayFileConfigurations(basePath string, isBaseRequired bool) ([]*FileBasedConfig, error) {
+	fileConfigs := []*FileBasedConfig{}
+	baseConfig, err := readFileConfiguration(basePath)
+	switch {
+	case err == nil:
+		fileConfigs = append(fileConfigs, baseConfig)
+	case !isBaseRequired && errors.Is(err, fs.ErrNotExist):
+		baseConfig = NewFileConfiguration()
+	default:
+		return nil, err
+	}
+	overlayPaths := GetOverlayFilePaths(basePath, resolveOverlayEnvironment(baseConfig))
+	for _, overlayPath := range overlayPaths[1:] {
+		overlayConfig, err := readOptionalFileConfiguration(overlayPath)
+		if err != nil {
+			return nil, err
+		}
+		fileConfigs = append(fileConfigs, overlayConfig)
+	}
+	return fileConfigs, nil
+}
+
+// resolveOverlayEnvironment returns the environment name used to select overlay files. The ENVIRONMENT process
+// environment variable takes precedence over the value defined in the base configuration file.
+func resolveOverlayEnvironment(baseConfig Contract) string {
+	environment, exists := os.LookupEnv(string(PropertyNameEnvironment))
+	if exists && strings.TrimSpace(environment) != "" {
+		return strings.TrimSpace(environment)
+	}
+	environment, _ = lookupNonBlankProperty(baseConfig, PropertyNameEnvironment)
+	return environment
+}
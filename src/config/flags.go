@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FlagBasedConfig represents a set of mapped configuration values. It also contains a mutex so it should ONLY be
+// passed around by-reference and never by-value.
+//
+// This configuration implementation is intended to load values from command-line flags such as
+// "--database-host=localhost", where the flag name is the property name in lower case with underscores replaced by
+// dashes.
+type FlagBasedConfig struct {
+	arguments     []string
+	configuration *ConfigurationMap
+}
+
+// GetAllProperties returns a copy of the map that represents all configuration values.
+func (c *FlagBasedConfig) GetAllProperties() map[string]string {
+	if c == nil || c.configuration == nil {
+		return map[string]string{}
+	}
+	return c.configuration.GetAllProperties()
+}
+
+// GetArguments returns the positional arguments that remained after the flags were parsed.
+func (c *FlagBasedConfig) GetArguments() []string {
+	if c == nil {
+		return []string{}
+	}
+	return append([]string{}, c.arguments...)
+}
+
+// GetProperty takes a property name and returns the matching string value from the configuration plus a boolean
+// describing whether the property name actually exists and was therefore valid.
+func (c *FlagBasedConfig) GetProperty(property PropertyName) (string, bool) {
+	if c == nil || c.configuration == nil {
+		return "", false
+	}
+	return c.configuration.GetProperty(property)
+}
+
+// HasProperty returns whether the property name exists within the configuration.
+func (c *FlagBasedConfig) HasProperty(property PropertyName) bool {
+	if c == nil || c.configuration == nil {
+		return false
+	}
+	return c.configuration.HasProperty(property)
+}
+
+// SetProperty sets the property with the given name to the provided value. Returns a boolean describing whether the
+// property was already present and was therefore overwritten.
+func (c *FlagBasedConfig) SetProperty(property PropertyName, value string) bool {
+	if c == nil || c.configuration == nil {
+		return false
+	}
+	return c.configuration.SetProperty(property, value)
+}
+
+// GetPropertyFlagName returns the command-line flag that sets the property (e.g., DATABASE_HOST becomes
+// "--database-host").
+func GetPropertyFlagName(property PropertyName) string {
+	return "--" + strings.ReplaceAll(strings.ToLower(string(property)), "_", "-")
+}
+
+// ParseFlagConfiguration parses the command-line arguments (without the program name) into a flag configuration using
+// the properties registered with the default schema registry. See ParseFlagConfigurationWithRegistry for the accepted
+// syntax. Returns the config struct instance plus any error that may have occurred.
+func ParseFlagConfiguration(args []string) (*FlagBasedConfig, error) {
+	return ParseFlagConfigurationWithRegistry(args, DefaultSchemaRegistry())
+}
+
+// ParseFlagConfigurationWithRegistry parses the command-line arguments (without the program name) into a flag
+// configuration. The following forms are accepted, with either one or two leading dashes:
+//
+//	--database-host=localhost
+//	--database-host localhost
+//	--debug                      (boolean properties only; equivalent to --debug=true)
+//
+// Every flag must map onto a property registered within the schema registry. Parsing stops at the first argument that
+// is not a flag or after a "--" terminator; the remaining arguments are available through GetArguments. Returns the
+// config struct instance plus any error that may have occurred.
+func ParseFlagConfigurationWithRegistry(args []string, registry *SchemaRegistry) (*FlagBasedConfig, error) {
+	if registry == nil {
+		return nil, ErrSchemaRegistryCannotBeNil
+	}
+	config := &FlagBasedConfig{
+		arguments:     []string{},
+		configuration: NewConfigurationMap(),
+	}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			config.arguments = append(config.arguments, args[i+1:]...)
+			break
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			config.arguments = append(config.arguments, args[i:]...)
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-"), "=")
+		property := PropertyName(strings.ReplaceAll(strings.ToUpper(name), "-", "_"))
+		schema, exists := registry.GetSchema(property)
+		if name == "" || !exists {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownConfigurationFlag, arg)
+		}
+		if !hasValue {
+			switch {
+			case schema.Type == PropertyTypeBool:
+				value = "true"
+			case i+1 < len(args):
+				i++
+				value = args[i]
+			default:
+				return nil, fmt.Errorf("%w: %s", ErrConfigurationFlagMissingValue, arg)
+			}
+		}
+		config.configuration.SetProperty(property, value)
+	}
+	return config, nil
+}
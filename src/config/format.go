@@ -0,0 +1,229 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigurationFileFormat represents the format of a configuration file.
+type ConfigurationFileFormat string
+
+const (
+	// ConfigurationFileFormatDotenv represents a dotenv (KEY=value) file. This is the format used for any file whose
+	// extension is not recognized as a structured format.
+	ConfigurationFileFormatDotenv ConfigurationFileFormat = "dotenv"
+
+	// ConfigurationFileFormatJSON represents a JSON file (".json").
+	ConfigurationFileFormatJSON ConfigurationFileFormat = "json"
+
+	// ConfigurationFileFormatTOML represents a TOML file (".toml").
+	ConfigurationFileFormatTOML ConfigurationFileFormat = "toml"
+
+	// ConfigurationFileFormatYAML represents a YAML file (".yaml" or ".yml").
+	ConfigurationFileFormatYAML ConfigurationFileFormat = "yaml"
+)
+
+// IsStructured returns whether the format supports nested and list-valued settings that must be flattened.
+func (f ConfigurationFileFormat) IsStructured() bool {
+	return f != ConfigurationFileFormatDotenv
+}
+
+// GetConfigurationFileFormat returns the format of the configuration file based upon the extension of its path.
+func GetConfigurationFileFormat(path string) ConfigurationFileFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return ConfigurationFileFormatJSON
+	case ".toml":
+		return ConfigurationFileFormatTOML
+	case ".yaml", ".yml":
+		return ConfigurationFileFormatYAML
+	}
+	return ConfigurationFileFormatDotenv
+}
+
+// FlattenConfigurationValues takes a decoded structured document and flattens it into a map of property names to
+// string values using the following key-mapping rule:
+//
+//   - nested keys are joined with an underscore (e.g., {"database": {"host": "x"}} becomes DATABASE_HOST)
+//   - keys are upper-cased and every character other than A-Z and 0-9 is replaced with an underscore (e.g.,
+//     "ssl-mode" becomes SSL_MODE)
+//   - lists of scalars are joined with commas so they can be read with GetPropertyAsList
+//   - lists containing maps or lists are flattened element-by-element using the zero-based index as the key (e.g.,
+//     {"hosts": [{"name": "a"}]} becomes HOSTS_0_NAME)
+//   - null values become blank strings and timestamps are formatted as RFC 3339
+//
+// Returns the flattened map plus an error if two different keys map onto the same property name.
+func FlattenConfigurationValues(document map[string]any) (map[string]string, error) {
+	flattened := map[string]string{}
+	if err := flattenConfigurationValue("", document, flattened); err != nil {
+		return nil, err
+	}
+	return flattened, nil
+}
+
+// readConfigurationFileMap reads the configuration file at the path in the format determined by its extension.
+// Returns the flattened map of property names to values, the names of the properties whose values are literal and must
+// never be expanded (see readDotenvMap), plus any error that may have occurred.
+func readConfigurationFileMap(path string) (map[string]string, []string, error) {
+	format := GetConfigurationFileFormat(path)
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !format.IsStructured() {
+		return readDotenvMap(contents)
+	}
+	document := map[string]any{}
+	switch format {
+	case ConfigurationFileFormatJSON:
+		decoder := json.NewDecoder(bytes.NewReader(contents))
+		decoder.UseNumber()
+		err = decoder.Decode(&document)
+	case ConfigurationFileFormatTOML:
+		err = toml.Unmarshal(contents, &document)
+	case ConfigurationFileFormatYAML:
+		err = yaml.Unmarshal(contents, &document)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s: %w", ErrInvalidConfigurationFileFormat, path, err)
+	}
+	values, err := FlattenConfigurationValues(document)
+	return values, nil, err
+}
+
+// readDotenvMap parses the contents of a dotenv file. The "${...}" references within unquoted and double-quoted values
+// are left in place so that they can be expanded across every configuration source (see InterpolatedConfig) rather
+// than only against the values defined earlier in the same file. Single-quoted values stay literal, as in any other
+// dotenv file, so the names of the single-quoted properties that contain references are returned as well. Returns the
+// map of property names to values, the names of the literal properties, plus any error that may have occurred.
+func readDotenvMap(contents []byte) (map[string]string, []string, error) {
+	// escaped references are unescaped by the dotenv parser for unquoted and double-quoted values, so they are expanded
+	// later; single-quoted values are literal and keep the escape, which marks them as literal
+	escapedContents := bytes.ReplaceAll(contents, []byte("${"), []byte(`\${`))
+	values, err := godotenv.UnmarshalBytes(escapedContents)
+	if err != nil {
+		return nil, nil, err
+	}
+	literals := []string{}
+	for name, value := range values {
+		if strings.Contains(value, `\${`) {
+			values[name] = strings.ReplaceAll(value, `\${`, "${")
+			literals = append(literals, name)
+		}
+	}
+	sort.Strings(literals)
+	return values, literals, nil
+}
+
+// flattenConfigurationValue recursively flattens the value under the given property name prefix into the provided
+// map. Returns any error that may have occurred.
+func flattenConfigurationValue(prefix string, value any, flattened map[string]string) error {
+	switch typedValue := value.(type) {
+	case map[string]any:
+		// sort the keys so that collisions are always reported in the same way
+		keys := make([]string, 0, len(typedValue))
+		for key := range typedValue {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := flattenConfigurationValue(joinFlattenedKey(prefix, key), typedValue[key], flattened); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[any]any:
+		stringKeyed := make(map[string]any, len(typedValue))
+		for key, nestedValue := range typedValue {
+			stringKeyed[fmt.Sprint(key)] = nestedValue
+		}
+		return flattenConfigurationValue(prefix, stringKeyed, flattened)
+	case []map[string]any:
+		list := make([]any, 0, len(typedValue))
+		for _, element := range typedValue {
+			list = append(list, element)
+		}
+		return flattenConfigurationValue(prefix, list, flattened)
+	case []any:
+		if !containsNestedValues(typedValue) {
+			elements := make([]string, 0, len(typedValue))
+			for _, element := range typedValue {
+				elements = append(elements, formatScalarConfigurationValue(element))
+			}
+			return setFlattenedValue(prefix, strings.Join(elements, ","), flattened)
+		}
+		for index, element := range typedValue {
+			if err := flattenConfigurationValue(
+				joinFlattenedKey(prefix, strconv.Itoa(index)), element, flattened,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return setFlattenedValue(prefix, formatScalarConfigurationValue(value), flattened)
+}
+
+// containsNestedValues returns whether any element of the list is itself a map or a list.
+func containsNestedValues(list []any) bool {
+	for _, element := range list {
+		switch element.(type) {
+		case map[string]any, map[any]any, []any, []map[string]any:
+			return true
+		}
+	}
+	return false
+}
+
+// formatScalarConfigurationValue returns the string representation of a scalar value decoded from a structured
+// configuration file.
+func formatScalarConfigurationValue(value any) string {
+	switch typedValue := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typedValue
+	case float64:
+		return strconv.FormatFloat(typedValue, 'f', -1, 64)
+	case time.Time:
+		return typedValue.Format(time.RFC3339)
+	}
+	return fmt.Sprint(value)
+}
+
+// joinFlattenedKey appends the key to the prefix using the flattening key-mapping rule.
+func joinFlattenedKey(prefix string, key string) string {
+	normalizedKey := strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(key))
+	if prefix == "" {
+		return normalizedKey
+	}
+	return prefix + "_" + normalizedKey
+}
+
+// setFlattenedValue stores the value under the property name. Returns an error if the property name already exists.
+func setFlattenedValue(property string, value string, flattened map[string]string) error {
+	if property == "" {
+		return fmt.Errorf("%w: top-level value must be a map", ErrInvalidConfigurationFileFormat)
+	}
+	if _, exists := flattened[property]; exists {
+		return fmt.Errorf("%w: %s", ErrConfigurationKeyCollision, property)
+	}
+	flattened[property] = value
+	return nil
+}
@@ -0,0 +1,293 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// interpolationValidatorContract is implemented by configurations that expand references to other properties and
+// can report the references that cannot be expanded.
+type interpolationValidatorContract interface {
+	ValidateInterpolation() error
+}
+
+// literalPropertyContract is implemented by configurations that hold literal values whose references must never be
+// expanded (e.g., single-quoted values within dotenv files).
+type literalPropertyContract interface {
+	isLiteralProperty(property PropertyName) bool
+}
+
+// InterpolatedConfig decorates another configuration so that "${OTHER_KEY}" and "${OTHER_KEY:-default}" references
+// within its values are expanded when they are read. References are resolved against the decorated configuration
+// using the following rules:
+//
+//   - "${OTHER_KEY}" is replaced with the expanded value of OTHER_KEY, or a blank string if it is missing
+//   - "${OTHER_KEY:-default}" is replaced with the default if OTHER_KEY is missing or blank; the default may itself
+//     contain references
+//   - "$${" is replaced with a literal "${" and is never expanded
+//
+// Values that cannot be expanded (e.g., because their references form a cycle) are returned as-is by GetProperty and
+// GetAllProperties; use GetInterpolatedProperty or ValidateInterpolation to find out why.
+type InterpolatedConfig struct {
+	source Contract
+}
+
+// GetAllProperties returns a copy of the map that represents all configuration values with their references
+// expanded.
+func (c *InterpolatedConfig) GetAllProperties() map[string]string {
+	if c == nil || c.source == nil {
+		return map[string]string{}
+	}
+	return interpolateAllProperties(c.source.GetAllProperties(), getInterpolationLookup(c.source))
+}
+
+// GetInterpolatedProperty takes a property name and returns the matching value with its references expanded, a
+// boolean describing whether the property name actually exists, and any error that occurred during the expansion.
+func (c *InterpolatedConfig) GetInterpolatedProperty(property PropertyName) (string, bool, error) {
+	if c == nil || c.source == nil {
+		return "", false, nil
+	}
+	return interpolateProperty(property, getInterpolationLookup(c.source))
+}
+
+// GetProperty takes a property name and returns the matching value with its references expanded, plus a boolean
+// describing whether the property name actually exists and was therefore valid.
+func (c *InterpolatedConfig) GetProperty(property PropertyName) (string, bool) {
+	if c == nil || c.source == nil {
+		return "", false
+	}
+	value, exists, err := interpolateProperty(property, getInterpolationLookup(c.source))
+	if err != nil {
+		return c.source.GetProperty(property)
+	}
+	return value, exists
+}
+
+// HasProperty returns whether the property name exists within the decorated configuration.
+func (c *InterpolatedConfig) HasProperty(property PropertyName) bool {
+	if c == nil || c.source == nil {
+		return false
+	}
+	return c.source.HasProperty(property)
+}
+
+// SetProperty sets the property with the given name to the provided value within the decorated configuration. The
+// value is stored as-is and expanded when read. Returns a boolean describing whether the property was already present
+// and was therefore overwritten.
+func (c *InterpolatedConfig) SetProperty(property PropertyName, value string) bool {
+	if c == nil || c.source == nil {
+		return false
+	}
+	return c.source.SetProperty(property, value)
+}
+
+// Source returns the decorated configuration.
+func (c *InterpolatedConfig) Source() Contract {
+	if c == nil {
+		return nil
+	}
+	return c.source
+}
+
+// ValidateInterpolation attempts to expand every property. Every problem found is collected and returned as a single
+// error. Returns nil if every property can be expanded.
+func (c *InterpolatedConfig) ValidateInterpolation() error {
+	if c == nil || c.source == nil {
+		return nil
+	}
+	return validateInterpolation(c.source.GetAllProperties(), getInterpolationLookup(c.source))
+}
+
+// NewInterpolatedConfiguration returns a new configuration struct instance that expands the references within the
+// values of the provided configuration.
+func NewInterpolatedConfiguration(source Contract) *InterpolatedConfig {
+	return &InterpolatedConfig{
+		source: source,
+	}
+}
+
+// ExpandValue expands the "${OTHER_KEY}" and "${OTHER_KEY:-default}" references within the value against the
+// provided configuration. Returns the expanded value plus any error that may have occurred.
+func ExpandValue(cfg Contract, value string) (string, error) {
+	if cfg == nil {
+		return "", ErrConfigurationCannotBeNil
+	}
+	return interpolateValue(value, getInterpolationLookup(cfg), []PropertyName{})
+}
+
+// escapeReferences returns the value with every "${" escaped as "$${" so that it is never expanded.
+func escapeReferences(value string) string {
+	return strings.ReplaceAll(value, "${", "$${")
+}
+
+// expandProperties returns a copy of the properties with their references expanded, looking up the referenced
+// properties within the properties first and within the process environment second. Properties for which isLiteral
+// returns true keep their values as-is. Returns the expanded properties plus an error if any of them cannot be
+// expanded (e.g., because their references form a cycle).
+func expandProperties(properties map[string]string, isLiteral func(PropertyName) bool) (map[string]string, error) {
+	lookup := func(property PropertyName) (string, bool) {
+		value, exists := properties[string(property)]
+		if !exists {
+			return os.LookupEnv(string(property))
+		}
+		if isLiteral != nil && isLiteral(property) {
+			return escapeReferences(value), true
+		}
+		return value, true
+	}
+	if err := validateInterpolation(properties, lookup); err != nil {
+		return nil, err
+	}
+	return interpolateAllProperties(properties, lookup), nil
+}
+
+// getInterpolationLookup returns the function used to look up the values of the configuration while expanding
+// references. Values are returned as they were written, before any references are expanded, except that the
+// references within literal values are escaped so that they are never expanded.
+func getInterpolationLookup(cfg Contract) func(PropertyName) (string, bool) {
+	switch typedConfig := cfg.(type) {
+	case *CompositeConfig:
+		return typedConfig.getInterpolationProperty
+	case *InterpolatedConfig:
+		if source := typedConfig.Source(); source != nil {
+			return getInterpolationLookup(source)
+		}
+	case literalPropertyContract:
+		return func(property PropertyName) (string, bool) {
+			value, exists := cfg.GetProperty(property)
+			if exists && typedConfig.isLiteralProperty(property) {
+				value = escapeReferences(value)
+			}
+			return value, exists
+		}
+	}
+	return cfg.GetProperty
+}
+
+// interpolateAllProperties returns a copy of the properties with their references expanded using the lookup function.
+// Properties that cannot be expanded keep their original values.
+func interpolateAllProperties(
+	properties map[string]string, lookup func(PropertyName) (string, bool),
+) map[string]string {
+	interpolated := make(map[string]string, len(properties))
+	for name, value := range properties {
+		if expanded, _, err := interpolateProperty(PropertyName(name), lookup); err == nil {
+			value = expanded
+		}
+		interpolated[name] = value
+	}
+	return interpolated
+}
+
+// interpolateProperty looks up the property and expands the references within its value. Returns the expanded value,
+// a boolean describing whether the property exists, and any error that may have occurred.
+func interpolateProperty(property PropertyName, lookup func(PropertyName) (string, bool)) (string, bool, error) {
+	value, exists := lookup(property)
+	if !exists {
+		return "", false, nil
+	}
+	expanded, err := interpolateValue(value, lookup, []PropertyName{property})
+	if err != nil {
+		return "", true, err
+	}
+	return expanded, true, nil
+}
+
+// interpolateValue expands the references within the value. The stack holds the properties currently being expanded
+// so that cycles can be detected. Returns the expanded value plus any error that may have occurred.
+func interpolateValue(value string, lookup func(PropertyName) (string, bool), stack []PropertyName) (string, error) {
+	if !strings.Contains(value, "${") {
+		return value, nil
+	}
+	var builder strings.Builder
+	for i := 0; i < len(value); {
+		if strings.HasPrefix(value[i:], "$${") {
+			builder.WriteString("${")
+			i += 3
+			continue
+		}
+		if !strings.HasPrefix(value[i:], "${") {
+			builder.WriteByte(value[i])
+			i++
+			continue
+		}
+		end := findReferenceEnd(value, i+2)
+		if end < 0 {
+			return "", fmt.Errorf("%w: unterminated reference in %q", ErrInvalidInterpolation, value)
+		}
+		expanded, err := interpolateReference(value[i+2:end], lookup, stack)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString(expanded)
+		i = end + 1
+	}
+	return builder.String(), nil
+}
+
+// interpolateReference expands a single reference given the text between its braces (e.g., "OTHER_KEY:-default").
+// Returns the expanded value plus any error that may have occurred.
+func interpolateReference(
+	reference string, lookup func(PropertyName) (string, bool), stack []PropertyName,
+) (string, error) {
+	name, defaultValue, hasDefault := strings.Cut(reference, ":-")
+	property := PropertyName(strings.TrimSpace(name))
+	if property == "" {
+		return "", fmt.Errorf("%w: blank reference in \"${%s}\"", ErrInvalidInterpolation, reference)
+	}
+	if slices.Contains(stack, property) {
+		cycle := append(slices.Clone(stack), property)
+		names := make([]string, 0, len(cycle))
+		for _, cycleProperty := range cycle {
+			names = append(names, string(cycleProperty))
+		}
+		return "", fmt.Errorf("%w: %s", ErrInterpolationCycle, strings.Join(names, " -> "))
+	}
+	value, exists := lookup(property)
+	if exists && value != "" {
+		return interpolateValue(value, lookup, append(slices.Clone(stack), property))
+	}
+	if hasDefault {
+		return interpolateValue(defaultValue, lookup, stack)
+	}
+	return "", nil
+}
+
+// findReferenceEnd returns the index of the brace that closes the reference whose contents begin at the start index,
+// taking nested references into account. Returns -1 if the reference is not terminated.
+func findReferenceEnd(value string, start int) int {
+	depth := 0
+	for i := start; i < len(value); i++ {
+		switch {
+		case strings.HasPrefix(value[i:], "${"):
+			depth++
+			i++
+		case value[i] == '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// validateInterpolation attempts to expand every property using the lookup function. Every problem found is collected
+// and returned as a single error. Returns nil if every property can be expanded.
+func validateInterpolation(properties map[string]string, lookup func(PropertyName) (string, bool)) error {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	problems := []error{}
+	for _, name := range names {
+		if _, _, err := interpolateProperty(PropertyName(name), lookup); err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(problems...)
+}
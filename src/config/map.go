@@ -1,6 +1,10 @@
 package config
 
-import "sync"
+import (
+	"slices"
+	"strings"
+	"sync"
+)
 
 // ConfigurationMap represents a set of mapped configuration values. It also contains a mutex so it should ONLY be
 // passed around by-reference and never by-value.
@@ -48,6 +52,49 @@ func (c *ConfigurationMap) HasProperty(property PropertyName) bool {
 	return exists
 }
 
+// ReplaceAllProperties atomically replaces every configuration value with the values from the provided map. Returns
+// a slice describing every property that was added, changed, or removed, sorted by property name.
+func (c *ConfigurationMap) ReplaceAllProperties(input map[string]string) []PropertyChange {
+	changes := []PropertyChange{}
+	if c == nil || c.configuration == nil {
+		return changes
+	}
+	newConfiguration := map[string]string{}
+	for key, val := range input {
+		newConfiguration[key] = val
+	}
+
+	// ensure we don't get a collision if two or more goroutines try to write concurrently
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, oldValue := range c.configuration {
+		newValue, newExists := newConfiguration[key]
+		if !newExists || newValue != oldValue {
+			changes = append(changes, PropertyChange{
+				NewExists: newExists,
+				NewValue:  newValue,
+				OldExists: true,
+				OldValue:  oldValue,
+				Property:  PropertyName(key),
+			})
+		}
+	}
+	for key, newValue := range newConfiguration {
+		if _, oldExists := c.configuration[key]; !oldExists {
+			changes = append(changes, PropertyChange{
+				NewExists: true,
+				NewValue:  newValue,
+				Property:  PropertyName(key),
+			})
+		}
+	}
+	c.configuration = newConfiguration
+	slices.SortFunc(changes, func(a, b PropertyChange) int {
+		return strings.Compare(string(a.Property), string(b.Property))
+	})
+	return changes
+}
+
 // SetProperty sets the property with the given name to the provided value. Returns a boolean describing whether the
 // property was already present and was therefore overwritten.
 func (c *ConfigurationMap) SetProperty(property PropertyName, value string) bool {
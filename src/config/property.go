@@ -4,12 +4,48 @@ package config
 type PropertyName string
 
 const (
+	// PropertyNameCacheAddresses represents the comma-separated "host:port" addresses of the Redis sentinels or cluster
+	// nodes.
+	PropertyNameCacheAddresses PropertyName = "CACHE_ADDRESSES"
+
+	// PropertyNameCacheDriver represents the cache implementation to use (e.g., "redis", "memory" or "tiered").
+	PropertyNameCacheDriver PropertyName = "CACHE_DRIVER"
+
+	// PropertyNameCacheEncryptionAllowUnencrypted represents whether cached values that were stored before encryption
+	// was turned on may still be read.
+	PropertyNameCacheEncryptionAllowUnencrypted PropertyName = "CACHE_ENCRYPTION_ALLOW_UNENCRYPTED"
+
+	// PropertyNameCacheEncryptionKeys represents the keys used to encrypt cached values, primary key first.
+	PropertyNameCacheEncryptionKeys PropertyName = "CACHE_ENCRYPTION_KEYS"
+
+	// PropertyNameCacheEncryptionKeysFile represents the file path from which to read the cache encryption keys.
+	PropertyNameCacheEncryptionKeysFile PropertyName = "CACHE_ENCRYPTION_KEYS_FILE"
+
+	// PropertyNameCacheEvictionPolicy represents the eviction policy of the in-memory cache (e.g., "lru" or "lfu").
+	PropertyNameCacheEvictionPolicy PropertyName = "CACHE_EVICTION_POLICY"
+
 	// PropertyNameCacheHost represents the cache host address.
 	PropertyNameCacheHost PropertyName = "CACHE_HOST"
 
 	// PropertyNameCacheIdentifier represents the cache identifier.
 	PropertyNameCacheIdentifier PropertyName = "CACHE_IDENTIFIER"
 
+	// PropertyNameCacheInvalidationChannel represents the Redis pub/sub channel used by the tiered cache to broadcast
+	// invalidations.
+	PropertyNameCacheInvalidationChannel PropertyName = "CACHE_INVALIDATION_CHANNEL"
+
+	// PropertyNameCacheL1TTL represents the longest time an item is held by the local tier of the tiered cache.
+	PropertyNameCacheL1TTL PropertyName = "CACHE_L1_TTL"
+
+	// PropertyNameCacheMaxEntries represents the maximum number of items held by the in-memory cache.
+	PropertyNameCacheMaxEntries PropertyName = "CACHE_MAX_ENTRIES"
+
+	// PropertyNameCacheNamespace represents the namespace added to every cache key (e.g., the service name).
+	PropertyNameCacheNamespace PropertyName = "CACHE_NAMESPACE"
+
+	// PropertyNameCacheNamespaceVersion represents the version added to every cache key after the namespace.
+	PropertyNameCacheNamespaceVersion PropertyName = "CACHE_NAMESPACE_VERSION"
+
 	// PropertyNameCacheUsername represents the cache username.
 	PropertyNameCacheUsername PropertyName = "CACHE_USERNAME"
 
@@ -22,9 +58,65 @@ const (
 	// PropertyNameCachePort represents the cache port.
 	PropertyNameCachePort PropertyName = "CACHE_PORT"
 
+	// PropertyNameCacheRedisMode represents how the Redis servers are deployed (e.g., "standalone", "sentinel" or
+	// "cluster").
+	PropertyNameCacheRedisMode PropertyName = "CACHE_REDIS_MODE"
+
+	// PropertyNameCacheSentinelMaster represents the name of the master monitored by the Redis sentinels.
+	PropertyNameCacheSentinelMaster PropertyName = "CACHE_SENTINEL_MASTER"
+
+	// PropertyNameCacheSentinelPassword represents the password used to authenticate with the Redis sentinels.
+	PropertyNameCacheSentinelPassword PropertyName = "CACHE_SENTINEL_PASSWORD"
+
+	// PropertyNameCacheSentinelPasswordFile represents the file path from which to read the Redis sentinel password.
+	PropertyNameCacheSentinelPasswordFile PropertyName = "CACHE_SENTINEL_PASSWORD_FILE"
+
+	// PropertyNameCacheSentinelUsername represents the username used to authenticate with the Redis sentinels.
+	PropertyNameCacheSentinelUsername PropertyName = "CACHE_SENTINEL_USERNAME"
+
+	// PropertyNameCacheTLSCAFile represents the path of the PEM file holding the certificate authorities used to
+	// verify the cache servers.
+	PropertyNameCacheTLSCAFile PropertyName = "CACHE_TLS_CA_FILE"
+
+	// PropertyNameCacheTLSCertFile represents the path of the PEM file holding the cache client certificate.
+	PropertyNameCacheTLSCertFile PropertyName = "CACHE_TLS_CERT_FILE"
+
+	// PropertyNameCacheTLSEnabled represents whether cache connections are secured with TLS.
+	PropertyNameCacheTLSEnabled PropertyName = "CACHE_TLS_ENABLED"
+
+	// PropertyNameCacheTLSInsecureSkipVerify represents whether verification of the cache server certificates is
+	// turned off.
+	PropertyNameCacheTLSInsecureSkipVerify PropertyName = "CACHE_TLS_INSECURE_SKIP_VERIFY"
+
+	// PropertyNameCacheTLSKeyFile represents the path of the PEM file holding the private key of the cache client
+	// certificate.
+	PropertyNameCacheTLSKeyFile PropertyName = "CACHE_TLS_KEY_FILE"
+
+	// PropertyNameCacheTLSServerName represents the name used to verify the cache server certificates.
+	PropertyNameCacheTLSServerName PropertyName = "CACHE_TLS_SERVER_NAME"
+
+	// PropertyNameConfigReloadInterval represents how often the configuration files are checked for changes. A blank
+	// or zero value turns live reloading off.
+	PropertyNameConfigReloadInterval PropertyName = "CONFIG_RELOAD_INTERVAL"
+
+	// PropertyNameDatabaseConnMaxIdleTime represents the longest time a database connection may sit idle in the pool.
+	PropertyNameDatabaseConnMaxIdleTime PropertyName = "DATABASE_CONN_MAX_IDLE_TIME"
+
+	// PropertyNameDatabaseConnMaxLifetime represents the longest time a database connection may be reused.
+	PropertyNameDatabaseConnMaxLifetime PropertyName = "DATABASE_CONN_MAX_LIFETIME"
+
 	// PropertyNameDatabaseHost represents the database host address.
 	PropertyNameDatabaseHost PropertyName = "DATABASE_HOST"
 
+	// PropertyNameDatabaseMaxIdleConns represents the maximum number of idle database connections kept in the pool.
+	PropertyNameDatabaseMaxIdleConns PropertyName = "DATABASE_MAX_IDLE_CONNS"
+
+	// PropertyNameDatabaseMaxOpenConns represents the maximum number of database connections open at once.
+	PropertyNameDatabaseMaxOpenConns PropertyName = "DATABASE_MAX_OPEN_CONNS"
+
+	// PropertyNameDatabaseMigrateOnStartup represents whether pending database migrations are applied at startup.
+	PropertyNameDatabaseMigrateOnStartup PropertyName = "DATABASE_MIGRATE_ON_STARTUP"
+
 	// PropertyNameDatabaseName represents the database name.
 	PropertyNameDatabaseName PropertyName = "DATABASE_NAME"
 
@@ -58,6 +150,9 @@ const (
 	// PropertyNameFeatureFlagSDKKeyFile represents the file path from which to read the feature flag SDK key.
 	PropertyNameFeatureFlagSDKKeyFile PropertyName = "FEATURE_FLAG_SDK_KEY_FILE"
 
+	// PropertyNameFeatureFlagPollingInterval represents how often the feature flag configuration is polled.
+	PropertyNameFeatureFlagPollingInterval PropertyName = "FEATURE_FLAG_POLLING_INTERVAL"
+
 	// PropertyNameGRPCPort represents the port on which the gRPC server will be listening.
 	PropertyNameGRPCPort PropertyName = "GRPC_PORT"
 
@@ -67,6 +162,9 @@ const (
 	// PropertyNameLoadEnvFromFile represents whether to load environment variables from a .env file.
 	PropertyNameLoadEnvFromFile PropertyName = "LOAD_ENV_FROM_FILE"
 
+	// PropertyNameLogLevel represents the minimum level at which messages are logged.
+	PropertyNameLogLevel PropertyName = "LOG_LEVEL"
+
 	// PropertyNameMailHost represents the mail server host address.
 	PropertyNameMailHost PropertyName = "MAIL_HOST"
 
@@ -88,41 +186,427 @@ const (
 	// PropertyNameMailUsername represents the mail server username.
 	PropertyNameMailUsername PropertyName = "MAIL_USERNAME"
 
+	// PropertyNameMetricsPath represents the HTTP path on which the service metrics are served for scraping. A blank
+	// value turns the endpoint off.
+	PropertyNameMetricsPath PropertyName = "METRICS_PATH"
+
+	// PropertyNameRateLimitAlgorithm represents the algorithm used to rate limit incoming gRPC calls (e.g.,
+	// "token_bucket" or "sliding_window").
+	PropertyNameRateLimitAlgorithm PropertyName = "RATE_LIMIT_ALGORITHM"
+
+	// PropertyNameRateLimitBurst represents the largest burst of incoming gRPC calls allowed by the token bucket.
+	PropertyNameRateLimitBurst PropertyName = "RATE_LIMIT_BURST"
+
+	// PropertyNameRateLimitRequests represents the number of incoming gRPC calls allowed per client and method within
+	// each rate limiting window. A zero value turns rate limiting off.
+	PropertyNameRateLimitRequests PropertyName = "RATE_LIMIT_REQUESTS"
+
+	// PropertyNameRateLimitTrustedProxies represents the number of trusted proxies (e.g., load balancers) in front of
+	// the gRPC-Gateway, which decides which "x-forwarded-for" address identifies the client of a proxied call.
+	PropertyNameRateLimitTrustedProxies PropertyName = "RATE_LIMIT_TRUSTED_PROXIES"
+
+	// PropertyNameRateLimitWindow represents the period over which the rate limit applies.
+	PropertyNameRateLimitWindow PropertyName = "RATE_LIMIT_WINDOW"
+
+	// PropertyNameSecretCacheTTL represents how long resolved secrets are cached before being read again.
+	PropertyNameSecretCacheTTL PropertyName = "SECRET_CACHE_TTL"
+
+	// PropertyNameSecretStoreURL represents the base URL of the HTTP secret store. A blank value turns the store off.
+	PropertyNameSecretStoreURL PropertyName = "SECRET_STORE_URL"
+
+	// PropertyNameSecretsDirectory represents the directory from which secret files are read (e.g., /run/secrets).
+	PropertyNameSecretsDirectory PropertyName = "SECRETS_DIRECTORY"
+
 	// PropertyNameServiceName represents the human-readable name of the service that is running.
 	PropertyNameServiceName PropertyName = "NAME"
 )
 
-// GetAvailableConfigurationKeys returns a slice of all available configuration property names.
+// GetAvailableConfigurationKeys returns a slice of all available configuration property names. This includes the
+// built-in properties as well as any properties registered through RegisterProperty or RegisterPropertyGroup.
 func GetAvailableConfigurationKeys() []PropertyName {
-	return []PropertyName{
-		PropertyNameCacheHost,
-		PropertyNameCacheIdentifier,
-		PropertyNameCacheUsername,
-		PropertyNameCachePassword,
-		PropertyNameCachePasswordFile,
-		PropertyNameCachePort,
-		PropertyNameDatabaseHost,
-		PropertyNameDatabaseName,
-		PropertyNameDatabasePassword,
-		PropertyNameDatabasePasswordFile,
-		PropertyNameDatabasePort,
-		PropertyNameDatabaseUsername,
-		PropertyNameDatabaseSSLMode,
-		PropertyNameDatabaseTimezone,
-		PropertyNameDebugMode,
-		PropertyNameEnvironment,
-		PropertyNameFeatureFlagSDKKey,
-		PropertyNameFeatureFlagSDKKeyFile,
-		PropertyNameGRPCPort,
-		PropertyNameHTTPPort,
-		PropertyNameLoadEnvFromFile,
-		PropertyNameMailHost,
-		PropertyNameMailPassword,
-		PropertyNameMailPasswordFile,
-		PropertyNameMailPort,
-		PropertyNameMailSenderAddress,
-		PropertyNameMailSenderName,
-		PropertyNameMailUsername,
-		PropertyNameServiceName,
+	return DefaultSchemaRegistry().GetPropertyNames()
+}
+
+// GetDefaultPropertySchemas returns a slice of schemas describing all built-in configuration properties.
+func GetDefaultPropertySchemas() []PropertySchema {
+	return []PropertySchema{
+		{
+			Name:        PropertyNameCacheAddresses,
+			Description: "Comma-separated host:port addresses of the Redis sentinels or cluster nodes; defaults to the host.",
+			Type:        PropertyTypeList,
+		},
+		{
+			Name:          PropertyNameCacheDriver,
+			AllowedValues: []string{"redis", "memory", "tiered"},
+			Default:       "redis",
+			Description:   "Cache implementation to use.",
+			Type:          PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameCacheEncryptionAllowUnencrypted,
+			Default:     "false",
+			Description: "Whether cached values stored before encryption was turned on may still be read; migration only.",
+			Type:        PropertyTypeBool,
+		},
+		{
+			Name:        PropertyNameCacheEncryptionKeys,
+			Description: "Comma-separated id:base64-key AES keys that encrypt cached values, primary first; blank is off.",
+			Sensitive:   true,
+			Type:        PropertyTypeList,
+		},
+		{
+			Name:        PropertyNameCacheEncryptionKeysFile,
+			Description: "File path from which to read the cache encryption keys.",
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:          PropertyNameCacheEvictionPolicy,
+			AllowedValues: []string{"lru", "lfu"},
+			Default:       "lru",
+			Description:   "Eviction policy of the in-memory cache (or local tier) when it is full.",
+			Type:          PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameCacheHost,
+			Description: "Host address of the cache server; required by the redis cache driver unless CACHE_ADDRESSES is set.",
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameCacheIdentifier,
+			Description: "Identifier of the cache instance (e.g., the Redis database number) for the redis driver.",
+			Type:        PropertyTypeInt,
+		},
+		{
+			Name:        PropertyNameCacheInvalidationChannel,
+			Default:     "cache:invalidations",
+			Description: "Redis pub/sub channel on which the tiered cache broadcasts invalidations.",
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameCacheL1TTL,
+			Default:     "30s",
+			Description: "Longest time an item is held by the local tier of the tiered cache.",
+			Type:        PropertyTypeDuration,
+		},
+		{
+			Name:        PropertyNameCacheMaxEntries,
+			Default:     "0",
+			Description: "Maximum number of items held by the in-memory cache (or local tier); zero means unbounded.",
+			Type:        PropertyTypeInt,
+		},
+		{
+			Name:        PropertyNameCacheNamespace,
+			Description: "Namespace added to every cache key (e.g., the service name); blank turns namespacing off.",
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameCacheNamespaceVersion,
+			Default:     "v1",
+			Description: "Version added to every cache key after the namespace; change it to abandon every cached item.",
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameCacheUsername,
+			Description: "Username used to authenticate with the cache server.",
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameCachePassword,
+			Description: "Password used to authenticate with the cache server.",
+			Sensitive:   true,
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameCachePasswordFile,
+			Description: "File path from which to read the cache password.",
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameCachePort,
+			Description: "Port of the cache server.",
+			Type:        PropertyTypeInt,
+		},
+		{
+			Name:          PropertyNameCacheRedisMode,
+			AllowedValues: []string{"standalone", "sentinel", "cluster"},
+			Default:       "standalone",
+			Description:   "How the Redis servers used by the redis and tiered cache drivers are deployed.",
+			Type:          PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameCacheSentinelMaster,
+			Description: "Name of the master monitored by the Redis sentinels; required in sentinel mode.",
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameCacheSentinelPassword,
+			Description: "Password used to authenticate with the Redis sentinels.",
+			Sensitive:   true,
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameCacheSentinelPasswordFile,
+			Description: "File path from which to read the Redis sentinel password.",
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameCacheSentinelUsername,
+			Description: "Username used to authenticate with the Redis sentinels.",
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameCacheTLSCAFile,
+			Description: "PEM file of the certificate authorities used to verify the cache servers instead of the system pool.",
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameCacheTLSCertFile,
+			Description: "PEM file of the client certificate presented to the cache servers.",
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameCacheTLSEnabled,
+			Default:     "false",
+			Description: "Whether cache connections are secured with TLS.",
+			Type:        PropertyTypeBool,
+		},
+		{
+			Name:        PropertyNameCacheTLSInsecureSkipVerify,
+			Default:     "false",
+			Description: "Whether verification of the cache server certificates is turned off; development only.",
+			Type:        PropertyTypeBool,
+		},
+		{
+			Name:        PropertyNameCacheTLSKeyFile,
+			Description: "PEM file of the private key of the client certificate.",
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameCacheTLSServerName,
+			Description: "Name used to verify the cache server certificates; defaults to the host being connected to.",
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameConfigReloadInterval,
+			Description: "How often the configuration files are checked for changes; blank or zero turns it off.",
+			Type:        PropertyTypeDuration,
+		},
+		{
+			Name:        PropertyNameDatabaseConnMaxIdleTime,
+			Description: "Longest time a database connection may sit idle in the pool; blank or zero keeps Go's default.",
+			Type:        PropertyTypeDuration,
+		},
+		{
+			Name:        PropertyNameDatabaseConnMaxLifetime,
+			Description: "Longest time a database connection may be reused; blank or zero keeps Go's default.",
+			Type:        PropertyTypeDuration,
+		},
+		{
+			Name:        PropertyNameDatabaseHost,
+			Description: "Host address of the database server.",
+			Required:    true,
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameDatabaseMaxIdleConns,
+			Description: "Maximum number of idle database connections kept in the pool; blank or zero keeps Go's default.",
+			Type:        PropertyTypeInt,
+		},
+		{
+			Name:        PropertyNameDatabaseMaxOpenConns,
+			Description: "Maximum number of database connections open at once; blank or zero means unlimited.",
+			Type:        PropertyTypeInt,
+		},
+		{
+			Name:        PropertyNameDatabaseMigrateOnStartup,
+			Default:     "false",
+			Description: "Whether pending database migrations are applied at startup before the service starts serving.",
+			Type:        PropertyTypeBool,
+		},
+		{
+			Name:        PropertyNameDatabaseName,
+			Description: "Name of the database.",
+			Required:    true,
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameDatabasePassword,
+			Description: "Password used to authenticate with the database server.",
+			Sensitive:   true,
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameDatabasePasswordFile,
+			Description: "File path from which to read the database password.",
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameDatabasePort,
+			Description: "Port of the database server.",
+			Type:        PropertyTypeInt,
+		},
+		{
+			Name:        PropertyNameDatabaseUsername,
+			Description: "Username used to authenticate with the database server.",
+			Required:    true,
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:          PropertyNameDatabaseSSLMode,
+			AllowedValues: []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"},
+			Description:   "SSL mode used when connecting to the database server.",
+			Type:          PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameDatabaseTimezone,
+			Description: "Default timezone of the database session.",
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameDebugMode,
+			Default:     "false",
+			Description: "Whether debugging mode is turned on.",
+			Type:        PropertyTypeBool,
+		},
+		{
+			Name:        PropertyNameEnvironment,
+			Description: "Environment on which the service is running.",
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameFeatureFlagSDKKey,
+			Description: "SDK key for the feature flag service.",
+			Sensitive:   true,
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameFeatureFlagSDKKeyFile,
+			Description: "File path from which to read the feature flag SDK key.",
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameFeatureFlagPollingInterval,
+			Default:     "1m",
+			Description: "How often the feature flag configuration is polled.",
+			Type:        PropertyTypeDuration,
+		},
+		{
+			Name:        PropertyNameGRPCPort,
+			Description: "Port on which the gRPC server will be listening.",
+			Required:    true,
+			Type:        PropertyTypeInt,
+		},
+		{
+			Name:        PropertyNameHTTPPort,
+			Description: "Port on which the HTTP service will be listening.",
+			Required:    true,
+			Type:        PropertyTypeInt,
+		},
+		{
+			Name:        PropertyNameLoadEnvFromFile,
+			Description: "Whether to load environment variables from a .env file.",
+			Type:        PropertyTypeBool,
+		},
+		{
+			Name:          PropertyNameLogLevel,
+			AllowedValues: []string{"debug", "info", "warn", "error"},
+			Description:   "Minimum level at which messages are logged; defaults to debug in debug mode and info otherwise.",
+			Type:          PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameMailHost,
+			Description: "Host address of the mail server.",
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameMailPassword,
+			Description: "Password used to authenticate with the mail server.",
+			Sensitive:   true,
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameMailPasswordFile,
+			Description: "File path from which to read the mail server password.",
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameMailPort,
+			Description: "Port of the mail server.",
+			Type:        PropertyTypeInt,
+		},
+		{
+			Name:        PropertyNameMailSenderAddress,
+			Description: "Email address of the mail sender.",
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameMailSenderName,
+			Description: "Human-readable name of the mail sender.",
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameMailUsername,
+			Description: "Username used to authenticate with the mail server.",
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameMetricsPath,
+			Default:     "/metrics",
+			Description: "HTTP path on which metrics are served in the Prometheus text format; blank turns it off.",
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:          PropertyNameRateLimitAlgorithm,
+			AllowedValues: []string{"token_bucket", "sliding_window"},
+			Default:       "token_bucket",
+			Description:   "Algorithm used to rate limit incoming gRPC calls.",
+			Type:          PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameRateLimitBurst,
+			Default:     "0",
+			Description: "Largest burst of calls allowed by the token bucket; zero uses RATE_LIMIT_REQUESTS.",
+			Type:        PropertyTypeInt,
+		},
+		{
+			Name:        PropertyNameRateLimitRequests,
+			Default:     "0",
+			Description: "Number of gRPC calls allowed per client and method in each window; zero turns it off.",
+			Type:        PropertyTypeInt,
+		},
+		{
+			Name:        PropertyNameRateLimitTrustedProxies,
+			Default:     "0",
+			Description: "Number of trusted proxies in front of the gateway whose forwarded client addresses are used.",
+			Type:        PropertyTypeInt,
+		},
+		{
+			Name:        PropertyNameRateLimitWindow,
+			Default:     "1s",
+			Description: "Period over which the rate limit applies.",
+			Type:        PropertyTypeDuration,
+		},
+		{
+			Name:        PropertyNameSecretCacheTTL,
+			Default:     "5m",
+			Description: "How long resolved secrets are cached before being read again.",
+			Type:        PropertyTypeDuration,
+		},
+		{
+			Name:        PropertyNameSecretStoreURL,
+			Description: "Base URL of the HTTP secret store; leave blank to turn the store off.",
+			Type:        PropertyTypeURL,
+		},
+		{
+			Name:        PropertyNameSecretsDirectory,
+			Default:     DefaultSecretsDirectory,
+			Description: "Directory from which secret files are read.",
+			Type:        PropertyTypeString,
+		},
+		{
+			Name:        PropertyNameServiceName,
+			Description: "Human-readable name of the service that is running.",
+			Type:        PropertyTypeString,
+		},
 	}
 }
@@ -0,0 +1,356 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// PropertyType represents the expected type of a configuration property value.
+type PropertyType string
+
+const (
+	// PropertyTypeBool represents a boolean value as accepted by strconv.ParseBool.
+	PropertyTypeBool PropertyType = "bool"
+
+	// PropertyTypeDuration represents a duration value as accepted by time.ParseDuration.
+	PropertyTypeDuration PropertyType = "duration"
+
+	// PropertyTypeFloat represents a 64-bit floating point value.
+	PropertyTypeFloat PropertyType = "float"
+
+	// PropertyTypeHostPort represents a "host:port" network address.
+	PropertyTypeHostPort PropertyType = "hostport"
+
+	// PropertyTypeInt represents a base-10 integer value.
+	PropertyTypeInt PropertyType = "int"
+
+	// PropertyTypeList represents a comma-separated list of values.
+	PropertyTypeList PropertyType = "list"
+
+	// PropertyTypeString represents a free-form string value.
+	PropertyTypeString PropertyType = "string"
+
+	// PropertyTypeURL represents an absolute URL containing both a scheme and a host.
+	PropertyTypeURL PropertyType = "url"
+)
+
+// isKnown returns whether the property type is one of the supported types.
+func (pt PropertyType) isKnown() bool {
+	switch pt {
+	case PropertyTypeBool, PropertyTypeDuration, PropertyTypeFloat, PropertyTypeHostPort, PropertyTypeInt,
+		PropertyTypeList, PropertyTypeString, PropertyTypeURL:
+		return true
+	}
+	return false
+}
+
+// validate checks whether the value can be parsed as the property type. Returns any error that may have occurred.
+func (pt PropertyType) validate(value string) error {
+	var err error
+	switch pt {
+	case PropertyTypeBool:
+		_, err = parseBool(value)
+	case PropertyTypeDuration:
+		_, err = parseDuration(value)
+	case PropertyTypeFloat:
+		_, err = parseFloat(value)
+	case PropertyTypeHostPort:
+		_, err = parseHostPort(value)
+	case PropertyTypeInt:
+		_, err = parseInt(value)
+	case PropertyTypeURL:
+		_, err = parseURL(value)
+	case PropertyTypeList, PropertyTypeString, "":
+		// any string value (including lists) is acceptable
+	default:
+		err = fmt.Errorf("unknown property type %q", pt)
+	}
+	return err
+}
+
+// PropertySchema describes the expectations for a single configuration property.
+type PropertySchema struct {
+	// AllowedValues optionally restricts the property to a fixed set of values. For list properties, every element of
+	// the list must be one of the allowed values.
+	AllowedValues []string
+
+	// Default is the value applied when the property is missing or blank. An empty string means "no default".
+	Default string
+
+	// Description is a human-readable explanation of the property.
+	Description string
+
+	// Name is the name of the property.
+	Name PropertyName
+
+	// Required describes whether the property must be present with a non-blank value after defaults are applied.
+	Required bool
+
+	// Sensitive describes whether the property holds a secret whose value must be masked in redacted output. Properties
+	// following the sensitive naming convention (see GetSensitivePropertySuffixes) are always treated as sensitive.
+	Sensitive bool
+
+	// Type is the expected type of the property value. An empty type is treated as PropertyTypeString.
+	Type PropertyType
+}
+
+// validateValue checks the non-blank value against the type and allowed values of the schema. Returns any error that
+// may have occurred.
+func (s PropertySchema) validateValue(value string) error {
+	if err := s.Type.validate(value); err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrInvalidPropertyValue, s.Name, err)
+	}
+	if len(s.AllowedValues) == 0 {
+		return nil
+	}
+	values := []string{value}
+	if s.Type == PropertyTypeList {
+		values, _ = parseList(value)
+	}
+	for _, v := range values {
+		if !slices.Contains(s.AllowedValues, v) {
+			return fmt.Errorf("%w: %s: %q is not one of %v", ErrPropertyValueNotAllowed, s.Name, v, s.AllowedValues)
+		}
+	}
+	return nil
+}
+
+// SchemaRegistry represents a set of property schemas used to apply defaults to and validate configurations. It also
+// contains a mutex so it should ONLY be passed around by-reference and never by-value.
+type SchemaRegistry struct {
+	mu      sync.Mutex
+	names   []PropertyName
+	schemas map[PropertyName]PropertySchema
+}
+
+// defaultSchemaRegistry is the registry used when loading configuration through the package-level loader functions.
+var defaultSchemaRegistry = NewSchemaRegistryFromSchemas(GetDefaultPropertySchemas())
+
+// DefaultSchemaRegistry returns the registry used when loading configuration through the package-level loader
+// functions.
+func DefaultSchemaRegistry() *SchemaRegistry {
+	return defaultSchemaRegistry
+}
+
+// ApplyDefaults sets the default value of every registered property that is either missing or blank within the
+// provided configuration.
+func (r *SchemaRegistry) ApplyDefaults(cfg Contract) {
+	if r == nil || cfg == nil {
+		return
+	}
+	for _, schema := range r.GetSchemas() {
+		if schema.Default == "" {
+			continue
+		}
+		if _, exists := lookupNonBlankProperty(cfg, schema.Name); !exists {
+			cfg.SetProperty(schema.Name, schema.Default)
+		}
+	}
+}
+
+// GetPropertyNames returns the names of all registered properties in registration order.
+func (r *SchemaRegistry) GetPropertyNames() []PropertyName {
+	if r == nil {
+		return []PropertyName{}
+	}
+
+	// ensure we don't get a collision if two or more goroutines try to read concurrently
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.names)
+}
+
+// GetSchema returns the schema registered for the property name plus a boolean describing whether it exists.
+func (r *SchemaRegistry) GetSchema(property PropertyName) (PropertySchema, bool) {
+	if r == nil || r.schemas == nil {
+		return PropertySchema{}, false
+	}
+
+	// ensure we don't get a collision if two or more goroutines try to read concurrently
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	schema, exists := r.schemas[property]
+	return schema, exists
+}
+
+// GetSchemas returns all registered schemas in registration order.
+func (r *SchemaRegistry) GetSchemas() []PropertySchema {
+	if r == nil || r.schemas == nil {
+		return []PropertySchema{}
+	}
+
+	// ensure we don't get a collision if two or more goroutines try to read concurrently
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	schemas := make([]PropertySchema, 0, len(r.names))
+	for _, name := range r.names {
+		schemas = append(schemas, r.schemas[name])
+	}
+	return schemas
+}
+
+// GetPropertyNamesWithPrefix returns the names of all registered properties that begin with the provided prefix in
+// registration order.
+func (r *SchemaRegistry) GetPropertyNamesWithPrefix(prefix string) []PropertyName {
+	names := []PropertyName{}
+	for _, name := range r.GetPropertyNames() {
+		if strings.HasPrefix(string(name), prefix) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// IsSensitive returns whether the property holds a secret, either because it was registered as sensitive or because
+// its name follows the sensitive naming convention.
+func (r *SchemaRegistry) IsSensitive(property PropertyName) bool {
+	if hasSensitivePropertySuffix(property) {
+		return true
+	}
+	schema, exists := r.GetSchema(property)
+	return exists && schema.Sensitive
+}
+
+// Register adds the schema to the registry. Returns an error if the schema is invalid or if a schema with the same
+// property name has already been registered.
+func (r *SchemaRegistry) Register(schema PropertySchema) error {
+	return r.RegisterGroup("", schema)
+}
+
+// RegisterGroup adds the schemas to the registry as a group. When the prefix is not blank, each schema name is
+// prepended with the prefix and an underscore separator (e.g., prefix "BILLING" and name "API_URL" are registered as
+// "BILLING_API_URL"). The group is registered atomically: if any schema is invalid or already registered, none of
+// them are added. Returns any error that may have occurred.
+func (r *SchemaRegistry) RegisterGroup(prefix string, schemas ...PropertySchema) error {
+	if r == nil {
+		return ErrSchemaRegistryCannotBeNil
+	}
+	normalizedSchemas := make([]PropertySchema, 0, len(schemas))
+	for _, schema := range schemas {
+		if prefix != "" && schema.Name != "" {
+			schema.Name = PropertyName(strings.TrimSuffix(prefix, "_") + "_" + string(schema.Name))
+		}
+		normalizedSchema, err := normalizePropertySchema(schema)
+		if err != nil {
+			return err
+		}
+		normalizedSchemas = append(normalizedSchemas, normalizedSchema)
+	}
+
+	// ensure we don't get a collision if two or more goroutines try to write concurrently
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.schemas == nil {
+		r.schemas = map[PropertyName]PropertySchema{}
+	}
+	seen := map[PropertyName]struct{}{}
+	for _, schema := range normalizedSchemas {
+		_, alreadyRegistered := r.schemas[schema.Name]
+		_, duplicatedInGroup := seen[schema.Name]
+		if alreadyRegistered || duplicatedInGroup {
+			return fmt.Errorf("%w: %s", ErrPropertyAlreadyRegistered, schema.Name)
+		}
+		seen[schema.Name] = struct{}{}
+	}
+	for _, schema := range normalizedSchemas {
+		r.schemas[schema.Name] = schema
+		r.names = append(r.names, schema.Name)
+	}
+	return nil
+}
+
+// Validate checks every registered property against the provided configuration, along with the references between
+// properties if the configuration expands them. Every problem found is collected and returned as a single error
+// wrapping ErrConfigurationValidationFailed. Returns nil if the validation checks pass.
+func (r *SchemaRegistry) Validate(cfg Contract) error {
+	if r == nil {
+		return nil
+	}
+	problems := []error{}
+	for _, schema := range r.GetSchemas() {
+		value, exists := lookupNonBlankProperty(cfg, schema.Name)
+		if !exists {
+			if schema.Required {
+				problems = append(problems, fmt.Errorf("%w: %s", ErrPropertyNotSet, schema.Name))
+			}
+			continue
+		}
+		if err := schema.validateValue(value); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	if interpolator, ok := cfg.(interpolationValidatorContract); ok {
+		if err := interpolator.ValidateInterpolation(); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %w", ErrConfigurationValidationFailed, errors.Join(problems...))
+	}
+	return nil
+}
+
+// NewSchemaRegistry returns a new empty schema registry struct instance.
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{
+		names:   []PropertyName{},
+		schemas: map[PropertyName]PropertySchema{},
+	}
+}
+
+// NewSchemaRegistryFromSchemas returns a new schema registry struct instance initialized with the provided schemas.
+//
+// This function panics if any of the schemas are invalid so it should only be used with known-good schemas.
+func NewSchemaRegistryFromSchemas(schemas []PropertySchema) *SchemaRegistry {
+	registry := NewSchemaRegistry()
+	for _, schema := range schemas {
+		if err := registry.Register(schema); err != nil {
+			panic(err)
+		}
+	}
+	return registry
+}
+
+// RegisterProperty adds the schema to the default schema registry so that the property is loaded, validated, and
+// listed alongside the built-in properties. Returns any error that may have occurred.
+func RegisterProperty(schema PropertySchema) error {
+	return DefaultSchemaRegistry().Register(schema)
+}
+
+// RegisterPropertyGroup adds the schemas to the default schema registry as a group under the provided prefix. See
+// SchemaRegistry.RegisterGroup for details on how the prefix is applied. Returns any error that may have occurred.
+func RegisterPropertyGroup(prefix string, schemas ...PropertySchema) error {
+	return DefaultSchemaRegistry().RegisterGroup(prefix, schemas...)
+}
+
+// applyDefaultsAndValidate applies the defaults from the default schema registry to the configuration and then
+// validates it. Returns any error that may have occurred.
+func applyDefaultsAndValidate(cfg Contract) error {
+	registry := DefaultSchemaRegistry()
+	registry.ApplyDefaults(cfg)
+	return registry.Validate(cfg)
+}
+
+// normalizePropertySchema fills in the implicit defaults of the schema and checks it for validity. Returns the
+// normalized schema plus any error that may have occurred.
+func normalizePropertySchema(schema PropertySchema) (PropertySchema, error) {
+	if schema.Name == "" {
+		return schema, fmt.Errorf("%w: property name cannot be blank", ErrInvalidPropertySchema)
+	}
+	if schema.Type == "" {
+		schema.Type = PropertyTypeString
+	}
+	if !schema.Type.isKnown() {
+		return schema, fmt.Errorf("%w: %s: unknown property type %q", ErrInvalidPropertySchema, schema.Name, schema.Type)
+	}
+	if schema.Default != "" {
+		if err := schema.validateValue(schema.Default); err != nil {
+			return schema, fmt.Errorf("%w: default value: %w", ErrInvalidPropertySchema, err)
+		}
+	}
+	schema.AllowedValues = slices.Clone(schema.AllowedValues)
+	return schema, nil
+}
@@ -0,0 +1,392 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultSecretCacheTTL is the length of time a resolved secret is cached when no TTL has been configured.
+	DefaultSecretCacheTTL time.Duration = 5 * time.Minute
+
+	// DefaultSecretsDirectory is the directory in which Docker mounts its secrets.
+	DefaultSecretsDirectory string = "/run/secrets"
+)
+
+// SecretResolver is an interface that represents a provider capable of resolving secrets by name.
+type SecretResolver interface {
+	// ResolveSecret takes a secret name and returns the secret value with any surrounding whitespace trimmed, a
+	// boolean describing whether the secret was found, and any error that may have occurred.
+	ResolveSecret(ctx context.Context, name string) (string, bool, error)
+}
+
+// SecretPropertyPair describes a secret property alongside the property holding the path of a file from which the
+// secret can be read instead (e.g., DATABASE_PASSWORD and DATABASE_PASSWORD_FILE).
+type SecretPropertyPair struct {
+	// FileProperty is the name of the property holding the path of the file containing the secret.
+	FileProperty PropertyName
+
+	// Property is the name of the property holding the secret itself.
+	Property PropertyName
+}
+
+// GetDefaultSecretPropertyPairs returns a slice of the built-in secret properties and their file path properties.
+func GetDefaultSecretPropertyPairs() []SecretPropertyPair {
+	return []SecretPropertyPair{
+		{FileProperty: PropertyNameCacheEncryptionKeysFile, Property: PropertyNameCacheEncryptionKeys},
+		{FileProperty: PropertyNameCachePasswordFile, Property: PropertyNameCachePassword},
+		{FileProperty: PropertyNameCacheSentinelPasswordFile, Property: PropertyNameCacheSentinelPassword},
+		{FileProperty: PropertyNameDatabasePasswordFile, Property: PropertyNameDatabasePassword},
+		{FileProperty: PropertyNameFeatureFlagSDKKeyFile, Property: PropertyNameFeatureFlagSDKKey},
+		{FileProperty: PropertyNameMailPasswordFile, Property: PropertyNameMailPassword},
+	}
+}
+
+// secretCacheEntry represents a single cached secret lookup.
+type secretCacheEntry struct {
+	expiresAt time.Time
+	found     bool
+	value     string
+}
+
+// secretCache caches secret lookups for a fixed length of time. It also contains a mutex so it should ONLY be passed
+// around by-reference and never by-value.
+type secretCache struct {
+	entries map[string]secretCacheEntry
+	mu      sync.Mutex
+	ttl     time.Duration
+}
+
+// ClearCache removes every cached secret so the next lookups go back to the underlying source.
+func (c *secretCache) ClearCache() {
+	if c == nil {
+		return
+	}
+	// ensure we don't get a collision if two or more goroutines try to write concurrently
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = map[string]secretCacheEntry{}
+}
+
+// resolve returns the cached secret if it has not expired, otherwise it calls the lookup function and caches the
+// result. Errors are never cached.
+func (c *secretCache) resolve(name string, lookup func() (string, bool, error)) (string, bool, error) {
+	if c == nil || c.ttl <= 0 {
+		value, found, err := lookup()
+		return strings.TrimSpace(value), found, err
+	}
+
+	// ensure we don't get a collision if two or more goroutines try to read concurrently
+	c.mu.Lock()
+	entry, exists := c.entries[name]
+	c.mu.Unlock()
+	if exists && time.Now().Before(entry.expiresAt) {
+		return entry.value, entry.found, nil
+	}
+
+	value, found, err := lookup()
+	if err != nil {
+		return "", false, err
+	}
+	value = strings.TrimSpace(value)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[name] = secretCacheEntry{
+		expiresAt: time.Now().Add(c.ttl),
+		found:     found,
+		value:     value,
+	}
+	return value, found, nil
+}
+
+// newSecretCache returns a new empty secret cache that keeps entries for the given TTL. A non-positive TTL turns
+// caching off.
+func newSecretCache(ttl time.Duration) *secretCache {
+	return &secretCache{
+		entries: map[string]secretCacheEntry{},
+		ttl:     ttl,
+	}
+}
+
+// FileSecretResolver resolves secrets by treating the secret name as the path of the file containing the secret.
+type FileSecretResolver struct {
+	*secretCache
+}
+
+// ResolveSecret reads the file at the path given by the name. A missing file is reported as not found while any other
+// read failure is returned as an error.
+func (r *FileSecretResolver) ResolveSecret(ctx context.Context, name string) (string, bool, error) {
+	if r == nil {
+		return "", false, ErrSecretResolverCannotBeNil
+	}
+	if name == "" {
+		return "", false, nil
+	}
+	return r.resolve(name, func() (string, bool, error) {
+		return readSecretFile(name)
+	})
+}
+
+// NewFileSecretResolver returns a new file-based secret resolver that caches each secret for the given TTL.
+func NewFileSecretResolver(ttl time.Duration) *FileSecretResolver {
+	return &FileSecretResolver{
+		secretCache: newSecretCache(ttl),
+	}
+}
+
+// DirectorySecretResolver resolves secrets from files within a directory, such as the one in which Docker mounts its
+// secrets ("/run/secrets/<name>").
+type DirectorySecretResolver struct {
+	*secretCache
+	directory string
+}
+
+// Directory returns the directory from which secrets are read.
+func (r *DirectorySecretResolver) Directory() string {
+	if r == nil {
+		return ""
+	}
+	return r.directory
+}
+
+// ResolveSecret reads the secret from the file with the given name inside the directory. If no such file exists, the
+// lower-cased name is tried as well so that DATABASE_PASSWORD can be found as "database_password". Names containing
+// path separators are never resolved.
+func (r *DirectorySecretResolver) ResolveSecret(ctx context.Context, name string) (string, bool, error) {
+	if r == nil {
+		return "", false, ErrSecretResolverCannotBeNil
+	}
+	if name == "" || r.directory == "" || name != filepath.Base(name) || name == "." || name == ".." {
+		return "", false, nil
+	}
+	return r.resolve(name, func() (string, bool, error) {
+		value, found, err := readSecretFile(filepath.Join(r.directory, name))
+		if err != nil || found {
+			return value, found, err
+		}
+		if lowerName := strings.ToLower(name); lowerName != name {
+			return readSecretFile(filepath.Join(r.directory, lowerName))
+		}
+		return "", false, nil
+	})
+}
+
+// NewDirectorySecretResolver returns a new directory-based secret resolver that reads from the given directory and
+// caches each secret for the given TTL.
+func NewDirectorySecretResolver(directory string, ttl time.Duration) *DirectorySecretResolver {
+	return &DirectorySecretResolver{
+		directory:   directory,
+		secretCache: newSecretCache(ttl),
+	}
+}
+
+// NewDockerSecretResolver returns a new directory-based secret resolver that reads from the Docker secrets directory
+// and caches each secret for the given TTL.
+func NewDockerSecretResolver(ttl time.Duration) *DirectorySecretResolver {
+	return NewDirectorySecretResolver(DefaultSecretsDirectory, ttl)
+}
+
+// EnvironmentSecretResolver resolves secrets by reading the configuration property with the same name as the secret.
+type EnvironmentSecretResolver struct {
+	*secretCache
+	configuration Contract
+}
+
+// ResolveSecret returns the value of the configuration property with the given name, or of the process environment
+// variable if the resolver has no configuration. A blank value is reported as found since an intentionally blank
+// secret is a valid scenario.
+func (r *EnvironmentSecretResolver) ResolveSecret(ctx context.Context, name string) (string, bool, error) {
+	if r == nil {
+		return "", false, ErrSecretResolverCannotBeNil
+	}
+	return r.resolve(name, func() (string, bool, error) {
+		if r.configuration == nil {
+			value, exists := os.LookupEnv(name)
+			return value, exists, nil
+		}
+		value, exists := r.configuration.GetProperty(PropertyName(name))
+		return value, exists, nil
+	})
+}
+
+// NewEnvironmentSecretResolver returns a new secret resolver that reads secrets from the provided configuration (or
+// from the process environment variables if the configuration is nil) and caches each secret for the given TTL.
+func NewEnvironmentSecretResolver(cfg Contract, ttl time.Duration) *EnvironmentSecretResolver {
+	return &EnvironmentSecretResolver{
+		configuration: cfg,
+		secretCache:   newSecretCache(ttl),
+	}
+}
+
+// SecretPropertyResolver resolves secret properties from the first source that has them, in the following order:
+//
+//  1. the file named by the paired file path property (e.g., DATABASE_PASSWORD_FILE), if set
+//  2. each of the additional resolvers, using the property name as the secret name
+//  3. the property itself (e.g., DATABASE_PASSWORD)
+type SecretPropertyResolver struct {
+	configuration Contract
+	fileProperty  map[PropertyName]PropertyName
+	fileResolver  *FileSecretResolver
+	mu            sync.Mutex
+	resolvers     []SecretResolver
+}
+
+// ClearCache removes every cached secret from the resolvers that cache their lookups.
+func (r *SecretPropertyResolver) ClearCache() {
+	if r == nil {
+		return
+	}
+	for _, resolver := range r.getResolvers() {
+		if cache, ok := resolver.(interface{ ClearCache() }); ok {
+			cache.ClearCache()
+		}
+	}
+}
+
+// RegisterSecretProperty pairs the secret property with the property holding the path of the file from which it can
+// be read. Registering the same property again replaces its file path property.
+func (r *SecretPropertyResolver) RegisterSecretProperty(pair SecretPropertyPair) error {
+	if r == nil {
+		return ErrSecretResolverCannotBeNil
+	}
+	if pair.Property == "" || pair.FileProperty == "" {
+		return fmt.Errorf("%w: secret and file path properties must both be named", ErrInvalidSecretPropertyPair)
+	}
+	// ensure we don't get a collision if two or more goroutines try to write concurrently
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fileProperty[pair.Property] = pair.FileProperty
+	return nil
+}
+
+// ResolveSecret resolves the secret property with the given name. This allows the property resolver to be used
+// anywhere a SecretResolver is expected.
+func (r *SecretPropertyResolver) ResolveSecret(ctx context.Context, name string) (string, bool, error) {
+	return r.ResolveSecretProperty(ctx, PropertyName(name))
+}
+
+// ResolveSecretProperty returns the value of the secret property with surrounding whitespace trimmed, a boolean
+// describing whether the secret was found in any of the sources, and any error that may have occurred.
+func (r *SecretPropertyResolver) ResolveSecretProperty(
+	ctx context.Context, property PropertyName,
+) (string, bool, error) {
+	if r == nil {
+		return "", false, ErrSecretResolverCannotBeNil
+	}
+	if r.configuration == nil {
+		return "", false, ErrConfigurationCannotBeNil
+	}
+
+	// ensure we don't get a collision if two or more goroutines try to read concurrently
+	r.mu.Lock()
+	fileProperty, isPaired := r.fileProperty[property]
+	r.mu.Unlock()
+	if isPaired {
+		if path, exists := lookupNonBlankProperty(r.configuration, fileProperty); exists {
+			value, found, err := r.fileResolver.ResolveSecret(ctx, path)
+			if err != nil {
+				return "", false, fmt.Errorf("%w: %s: %w", ErrCannotResolveSecret, fileProperty, err)
+			}
+			if !found {
+				return "", false, fmt.Errorf("%w: %s: file %q does not exist", ErrCannotResolveSecret, fileProperty,
+					path)
+			}
+			return value, true, nil
+		}
+	}
+
+	for _, resolver := range r.resolvers {
+		value, found, err := resolver.ResolveSecret(ctx, string(property))
+		if err != nil {
+			return "", false, fmt.Errorf("%w: %s: %w", ErrCannotResolveSecret, property, err)
+		}
+		if found {
+			return value, true, nil
+		}
+	}
+
+	value, exists := r.configuration.GetProperty(property)
+	return strings.TrimSpace(value), exists, nil
+}
+
+// getResolvers returns every resolver consulted by the property resolver, including the file resolver.
+func (r *SecretPropertyResolver) getResolvers() []SecretResolver {
+	resolvers := []SecretResolver{r.fileResolver}
+	return append(resolvers, r.resolvers...)
+}
+
+// NewSecretPropertyResolver returns a new secret property resolver for the configuration that knows about the
+// built-in secret property pairs. Secret files are cached for the given TTL and the additional resolvers are consulted
+// in order before falling back to the property itself.
+func NewSecretPropertyResolver(
+	cfg Contract, ttl time.Duration, resolvers ...SecretResolver,
+) *SecretPropertyResolver {
+	resolver := &SecretPropertyResolver{
+		configuration: cfg,
+		fileProperty:  map[PropertyName]PropertyName{},
+		fileResolver:  NewFileSecretResolver(ttl),
+		resolvers:     []SecretResolver{},
+	}
+	for _, pair := range GetDefaultSecretPropertyPairs() {
+		resolver.fileProperty[pair.Property] = pair.FileProperty
+	}
+	for _, additionalResolver := range resolvers {
+		if additionalResolver != nil {
+			resolver.resolvers = append(resolver.resolvers, additionalResolver)
+		}
+	}
+	return resolver
+}
+
+// NewSecretPropertyResolverFromConfig returns a new secret property resolver built from the secret settings in the
+// configuration. Secrets are read from the secrets directory (if it exists) and the HTTP secret store (if its URL is
+// set) before falling back to the properties themselves. Returns the resolver plus any error that may have occurred.
+func NewSecretPropertyResolverFromConfig(cfg Contract) (*SecretPropertyResolver, error) {
+	if cfg == nil {
+		return nil, ErrConfigurationCannotBeNil
+	}
+	ttl, err := GetPropertyAsDurationWithDefault(cfg, PropertyNameSecretCacheTTL, DefaultSecretCacheTTL)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := []SecretResolver{}
+	directory, exists := lookupNonBlankProperty(cfg, PropertyNameSecretsDirectory)
+	if !exists {
+		directory = DefaultSecretsDirectory
+	}
+	if info, err := os.Stat(directory); err == nil && info.IsDir() {
+		resolvers = append(resolvers, NewDirectorySecretResolver(directory, ttl))
+	}
+	storeURL, err := GetPropertyAsURLWithDefault(cfg, PropertyNameSecretStoreURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if storeURL != nil {
+		storeResolver, err := NewHTTPSecretResolver(nil, storeURL.String(), ttl)
+		if err != nil {
+			return nil, err
+		}
+		resolvers = append(resolvers, storeResolver)
+	}
+	return NewSecretPropertyResolver(cfg, ttl, resolvers...), nil
+}
+
+// readSecretFile reads the secret from the file at the path. Returns the contents, a boolean describing whether the
+// file exists, and any other error that may have occurred while reading it.
+func readSecretFile(path string) (string, bool, error) {
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return string(contents), true, nil
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	gohttp "net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	servicehttp "github.com/sepulchrestudios/go-service/src/http"
+)
+
+// SecretStoreResponse represents the JSON document returned by an HTTP secret store for a single secret.
+type SecretStoreResponse struct {
+	// Found describes whether the secret exists within the store.
+	Found bool `json:"found"`
+
+	// Name is the name of the secret that was requested.
+	Name string `json:"name"`
+
+	// Value is the value of the secret. It is blank when the secret was not found.
+	Value string `json:"value"`
+}
+
+// HTTPSecretResolver resolves secrets from an HTTP secret store. Each secret is requested with a GET to
+// "<base URL>/<name>" and the store must reply with a SecretStoreResponse JSON document. A 404 response means that the
+// secret does not exist, whereas any other response without a 2xx status is treated as a failed request.
+type HTTPSecretResolver struct {
+	*secretCache
+	baseURL string
+	client  servicehttp.StatusContract
+}
+
+// BaseURL returns the base URL of the secret store.
+func (r *HTTPSecretResolver) BaseURL() string {
+	if r == nil {
+		return ""
+	}
+	return r.baseURL
+}
+
+// ResolveSecret requests the secret with the given name from the secret store. Returns the secret value, a boolean
+// describing whether the store knows the secret, and any error that may have occurred. Failed requests (including
+// responses with an error status) return ErrSecretStoreRequestFailed and are never cached.
+func (r *HTTPSecretResolver) ResolveSecret(ctx context.Context, name string) (string, bool, error) {
+	if r == nil || r.client == nil {
+		return "", false, ErrSecretResolverCannotBeNil
+	}
+	if name == "" {
+		return "", false, nil
+	}
+	return r.resolve(name, func() (string, bool, error) {
+		secretURL := r.baseURL + "/" + url.PathEscape(name)
+		statusCode, body, err := r.client.SendWithStatus(ctx, servicehttp.HTTPMethodGet, secretURL)
+		if err != nil {
+			return "", false, fmt.Errorf("%w: %w", ErrSecretStoreRequestFailed, err)
+		}
+		if statusCode == gohttp.StatusNotFound {
+			return "", false, nil
+		}
+		if statusCode < 200 || statusCode > 299 {
+			return "", false, fmt.Errorf("%w: unexpected status %d", ErrSecretStoreRequestFailed, statusCode)
+		}
+		response := SecretStoreResponse{}
+		if err = json.Unmarshal(body, &response); err != nil {
+			return "", false, fmt.Errorf("%w: %w", ErrInvalidSecretStoreResponse, err)
+		}
+		return response.Value, response.Found, nil
+	})
+}
+
+// NewHTTPSecretResolver returns a new secret resolver that requests secrets from the store at the base URL using the
+// provided HTTP client (or a default client if it is nil) and caches each secret for the given TTL. Returns the
+// resolver plus any error that may have occurred.
+func NewHTTPSecretResolver(
+	client servicehttp.StatusContract, baseURL string, ttl time.Duration,
+) (*HTTPSecretResolver, error) {
+	parsedURL, err := parseURL(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSecretStoreURL, err)
+	}
+	if client == nil {
+		client, err = servicehttp.NewHTTPClient()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &HTTPSecretResolver{
+		baseURL:     strings.TrimSuffix(parsedURL.String(), "/"),
+		client:      client,
+		secretCache: newSecretCache(ttl),
+	}, nil
+}
+
+// LocalSecretStore is an in-memory HTTP secret store that speaks the same protocol expected by HTTPSecretResolver. It
+// is intended as a local stand-in for a real secret store during development and testing (e.g., when served through
+// httptest.NewServer). It also contains a mutex so it should ONLY be passed around by-reference and never by-value.
+type LocalSecretStore struct {
+	mu      sync.Mutex
+	secrets map[string]string
+}
+
+// DeleteSecret removes the secret with the given name from the store.
+func (s *LocalSecretStore) DeleteSecret(name string) {
+	if s == nil {
+		return
+	}
+	// ensure we don't get a collision if two or more goroutines try to write concurrently
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.secrets, name)
+}
+
+// ServeHTTP replies to GET requests for "/<name>" with a SecretStoreResponse JSON document.
+func (s *LocalSecretStore) ServeHTTP(w gohttp.ResponseWriter, r *gohttp.Request) {
+	if s == nil {
+		gohttp.Error(w, ErrSecretResolverCannotBeNil.Error(), gohttp.StatusInternalServerError)
+		return
+	}
+	if r.Method != gohttp.MethodGet {
+		gohttp.Error(w, gohttp.StatusText(gohttp.StatusMethodNotAllowed), gohttp.StatusMethodNotAllowed)
+		return
+	}
+	name := strings.TrimPrefix(r.URL.Path, "/")
+
+	// ensure we don't get a collision if two or more goroutines try to read concurrently
+	s.mu.Lock()
+	value, found := s.secrets[name]
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(SecretStoreResponse{
+		Found: found,
+		Name:  name,
+		Value: value,
+	})
+}
+
+// SetSecret stores the secret under the given name, replacing any existing value.
+func (s *LocalSecretStore) SetSecret(name string, value string) {
+	if s == nil {
+		return
+	}
+	// ensure we don't get a collision if two or more goroutines try to write concurrently
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.secrets[name] = value
+}
+
+// NewLocalSecretStore returns a new local secret store pre-populated with a copy of the provided secrets.
+func NewLocalSecretStore(secrets map[string]string) *LocalSecretStore {
+	store := &LocalSecretStore{
+		secrets: map[string]string{},
+	}
+	for name, value := range secrets {
+		store.secrets[name] = value
+	}
+	return store
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"context"
+	"errors"
+	gohttp "net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPSecretResolverStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		wantValue  string
+		wantFound  bool
+		wantErr    error
+	}{
+		{"found", gohttp.StatusOK, `{"found":true,"name":"db","value":" secret "}`, "secret", true, nil},
+		{"not found document", gohttp.StatusOK, `{"found":false,"name":"db"}`, "", false, nil},
+		{"not found status", gohttp.StatusNotFound, `{"error":"no such secret"}`, "", false, nil},
+		{"unauthorized", gohttp.StatusUnauthorized, `{"error":"bad token"}`, "", false, ErrSecretStoreRequestFailed},
+		{"forbidden", gohttp.StatusForbidden, `{"error":"denied"}`, "", false, ErrSecretStoreRequestFailed},
+		{"server error", gohttp.StatusInternalServerError, `{}`, "", false, ErrSecretStoreRequestFailed},
+		{"invalid document", gohttp.StatusOK, `not json`, "", false, ErrInvalidSecretStoreResponse},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(gohttp.HandlerFunc(func(w gohttp.ResponseWriter, r *gohttp.Request) {
+				w.WriteHeader(test.statusCode)
+				_, _ = w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+			resolver, err := NewHTTPSecretResolver(nil, server.URL, time.Minute)
+			if err != nil {
+				t.Fatalf("NewHTTPSecretResolver() error = %v", err)
+			}
+			value, found, err := resolver.ResolveSecret(context.Background(), "db")
+			if !errors.Is(err, test.wantErr) || (test.wantErr == nil && err != nil) {
+				t.Fatalf("ResolveSecret() error = %v, want %v", err, test.wantErr)
+			}
+			if value != test.wantValue || found != test.wantFound {
+				t.Errorf("ResolveSecret() = (%q, %v), want (%q, %v)", value, found, test.wantValue, test.wantFound)
+			}
+		})
+	}
+}
+
+func TestHTTPSecretResolverDoesNotCacheErrors(t *testing.T) {
+	store := NewLocalSecretStore(map[string]string{"db": "secret"})
+	isFailing := atomic.Bool{}
+	isFailing.Store(true)
+	server := httptest.NewServer(gohttp.HandlerFunc(func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		if isFailing.Load() {
+			gohttp.Error(w, `{"error":"unavailable"}`, gohttp.StatusServiceUnavailable)
+			return
+		}
+		store.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	resolver, err := NewHTTPSecretResolver(nil, server.URL, time.Hour)
+	if err != nil {
+		t.Fatalf("NewHTTPSecretResolver() error = %v", err)
+	}
+	if _, _, err = resolver.ResolveSecret(context.Background(), "db"); !errors.Is(err, ErrSecretStoreRequestFailed) {
+		t.Fatalf("ResolveSecret() error = %v, want %v", err, ErrSecretStoreRequestFailed)
+	}
+	isFailing.Store(false)
+	value, found, err := resolver.ResolveSecret(context.Background(), "db")
+	if err != nil || !found || value != "secret" {
+		t.Errorf("ResolveSecret() = (%q, %v, %v), want (\"secret\", true, nil)", value, found, err)
+	}
+}
@@ -0,0 +1,215 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HostPort represents a network address that has been split into its host and port components.
+type HostPort struct {
+	Host string
+	Port string
+}
+
+// String returns the "host:port" representation of the address.
+func (hp HostPort) String() string {
+	return net.JoinHostPort(hp.Host, hp.Port)
+}
+
+// GetPropertyAsBool takes a configuration and a property name and returns the property value parsed as a boolean plus
+// any error that may have occurred. Accepted values are the same as those accepted by strconv.ParseBool.
+func GetPropertyAsBool(cfg Contract, property PropertyName) (bool, error) {
+	return getTypedProperty(cfg, property, parseBool)
+}
+
+// GetPropertyAsBoolWithDefault performs the same operation as GetPropertyAsBool but returns the default value when the
+// property is either missing or blank.
+func GetPropertyAsBoolWithDefault(cfg Contract, property PropertyName, defaultValue bool) (bool, error) {
+	return getTypedPropertyWithDefault(cfg, property, defaultValue, parseBool)
+}
+
+// GetPropertyAsDuration takes a configuration and a property name and returns the property value parsed as a
+// duration plus any error that may have occurred. Accepted values are the same as those accepted by
+// time.ParseDuration (e.g., "30s", "1m30s").
+func GetPropertyAsDuration(cfg Contract, property PropertyName) (time.Duration, error) {
+	return getTypedProperty(cfg, property, parseDuration)
+}
+
+// GetPropertyAsDurationWithDefault performs the same operation as GetPropertyAsDuration but returns the default value
+// when the property is either missing or blank.
+func GetPropertyAsDurationWithDefault(
+	cfg Contract, property PropertyName, defaultValue time.Duration,
+) (time.Duration, error) {
+	return getTypedPropertyWithDefault(cfg, property, defaultValue, parseDuration)
+}
+
+// GetPropertyAsFloat takes a configuration and a property name and returns the property value parsed as a 64-bit
+// float plus any error that may have occurred.
+func GetPropertyAsFloat(cfg Contract, property PropertyName) (float64, error) {
+	return getTypedProperty(cfg, property, parseFloat)
+}
+
+// GetPropertyAsFloatWithDefault performs the same operation as GetPropertyAsFloat but returns the default value when
+// the property is either missing or blank.
+func GetPropertyAsFloatWithDefault(cfg Contract, property PropertyName, defaultValue float64) (float64, error) {
+	return getTypedPropertyWithDefault(cfg, property, defaultValue, parseFloat)
+}
+
+// GetPropertyAsHostPort takes a configuration and a property name and returns the property value parsed as a
+// "host:port" network address plus any error that may have occurred.
+func GetPropertyAsHostPort(cfg Contract, property PropertyName) (HostPort, error) {
+	return getTypedProperty(cfg, property, parseHostPort)
+}
+
+// GetPropertyAsHostPortWithDefault performs the same operation as GetPropertyAsHostPort but returns the default value
+// when the property is either missing or blank.
+func GetPropertyAsHostPortWithDefault(cfg Contract, property PropertyName, defaultValue HostPort) (HostPort, error) {
+	return getTypedPropertyWithDefault(cfg, property, defaultValue, parseHostPort)
+}
+
+// GetPropertyAsInt takes a configuration and a property name and returns the property value parsed as an integer plus
+// any error that may have occurred.
+func GetPropertyAsInt(cfg Contract, property PropertyName) (int, error) {
+	return getTypedProperty(cfg, property, parseInt)
+}
+
+// GetPropertyAsIntWithDefault performs the same operation as GetPropertyAsInt but returns the default value when the
+// property is either missing or blank.
+func GetPropertyAsIntWithDefault(cfg Contract, property PropertyName, defaultValue int) (int, error) {
+	return getTypedPropertyWithDefault(cfg, property, defaultValue, parseInt)
+}
+
+// GetPropertyAsList takes a configuration and a property name and returns the property value split on commas. Each
+// element has its surrounding whitespace trimmed and empty elements are discarded.
+func GetPropertyAsList(cfg Contract, property PropertyName) ([]string, error) {
+	return getTypedProperty(cfg, property, parseList)
+}
+
+// GetPropertyAsListWithDefault performs the same operation as GetPropertyAsList but returns the default value when the
+// property is either missing or blank.
+func GetPropertyAsListWithDefault(cfg Contract, property PropertyName, defaultValue []string) ([]string, error) {
+	return getTypedPropertyWithDefault(cfg, property, defaultValue, parseList)
+}
+
+// GetPropertyAsURL takes a configuration and a property name and returns the property value parsed as an absolute URL
+// plus any error that may have occurred. The URL must contain both a scheme and a host.
+func GetPropertyAsURL(cfg Contract, property PropertyName) (*url.URL, error) {
+	return getTypedProperty(cfg, property, parseURL)
+}
+
+// GetPropertyAsURLWithDefault performs the same operation as GetPropertyAsURL but returns the default value when the
+// property is either missing or blank.
+func GetPropertyAsURLWithDefault(cfg Contract, property PropertyName, defaultValue *url.URL) (*url.URL, error) {
+	return getTypedPropertyWithDefault(cfg, property, defaultValue, parseURL)
+}
+
+// getTypedProperty resolves the property from the configuration and converts it with the provided parsing function.
+// Returns an error wrapping ErrPropertyNotSet if the property is missing or blank, or an error wrapping
+// ErrInvalidPropertyValue if the value cannot be parsed.
+func getTypedProperty[T any](cfg Contract, property PropertyName, parse func(string) (T, error)) (T, error) {
+	var zeroValue T
+	value, exists := lookupNonBlankProperty(cfg, property)
+	if !exists {
+		return zeroValue, fmt.Errorf("%w: %s", ErrPropertyNotSet, property)
+	}
+	parsed, err := parse(value)
+	if err != nil {
+		return zeroValue, fmt.Errorf("%w: %s: %w", ErrInvalidPropertyValue, property, err)
+	}
+	return parsed, nil
+}
+
+// getTypedPropertyWithDefault resolves the property from the configuration and converts it with the provided parsing
+// function. Returns the default value if the property is missing or blank. If the value cannot be parsed, the default
+// value is returned alongside an error wrapping ErrInvalidPropertyValue.
+func getTypedPropertyWithDefault[T any](
+	cfg Contract, property PropertyName, defaultValue T, parse func(string) (T, error),
+) (T, error) {
+	value, exists := lookupNonBlankProperty(cfg, property)
+	if !exists {
+		return defaultValue, nil
+	}
+	parsed, err := parse(value)
+	if err != nil {
+		return defaultValue, fmt.Errorf("%w: %s: %w", ErrInvalidPropertyValue, property, err)
+	}
+	return parsed, nil
+}
+
+// lookupNonBlankProperty returns the whitespace-trimmed property value plus a boolean describing whether the property
+// both exists and contains a non-blank value.
+func lookupNonBlankProperty(cfg Contract, property PropertyName) (string, bool) {
+	if cfg == nil {
+		return "", false
+	}
+	value, exists := cfg.GetProperty(property)
+	value = strings.TrimSpace(value)
+	if !exists || value == "" {
+		return "", false
+	}
+	return value, true
+}
+
+// parseBool parses the value as a boolean.
+func parseBool(value string) (bool, error) {
+	return strconv.ParseBool(value)
+}
+
+// parseDuration parses the value as a duration.
+func parseDuration(value string) (time.Duration, error) {
+	return time.ParseDuration(value)
+}
+
+// parseFloat parses the value as a 64-bit float.
+func parseFloat(value string) (float64, error) {
+	return strconv.ParseFloat(value, 64)
+}
+
+// parseHostPort parses the value as a "host:port" network address with a numeric port.
+func parseHostPort(value string) (HostPort, error) {
+	host, port, err := net.SplitHostPort(value)
+	if err != nil {
+		return HostPort{}, err
+	}
+	portNumber, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return HostPort{}, fmt.Errorf("invalid port %q: %w", port, err)
+	}
+	return HostPort{
+		Host: host,
+		Port: strconv.FormatUint(portNumber, 10),
+	}, nil
+}
+
+// parseInt parses the value as a base-10 integer.
+func parseInt(value string) (int, error) {
+	return strconv.Atoi(value)
+}
+
+// parseList splits the value on commas, trims each element, and discards empty elements.
+func parseList(value string) ([]string, error) {
+	list := []string{}
+	for _, element := range strings.Split(value, ",") {
+		element = strings.TrimSpace(element)
+		if element != "" {
+			list = append(list, element)
+		}
+	}
+	return list, nil
+}
+
+// parseURL parses the value as an absolute URL that contains both a scheme and a host.
+func parseURL(value string) (*url.URL, error) {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("url %q must contain a scheme and a host", value)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+)
+
+// PropertyChange describes a change to a single configuration property.
+type PropertyChange struct {
+	// NewExists describes whether the property exists after the change.
+	NewExists bool
+
+	// NewValue is the value of the property after the change.
+	NewValue string
+
+	// OldExists describes whether the property existed before the change.
+	OldExists bool
+
+	// OldValue is the value of the property before the change.
+	OldValue string
+
+	// Property is the name of the property that changed.
+	Property PropertyName
+
+	// Version is the configuration version produced by the change.
+	Version uint64
+}
+
+// ChangeHandlerFunc defines the function signature for configuration change handler functions.
+type ChangeHandlerFunc func(change PropertyChange)
+
+// ReloadValidatorFunc defines the function signature for functions that decide whether a reloaded candidate
+// configuration may replace the current values. Returning an error rejects the reload.
+type ReloadValidatorFunc func(candidate Contract) error
+
+// WatchContract is an interface that represents a configuration source that can be reloaded while the service is
+// running and can notify subscribers about the resulting changes.
+type WatchContract interface {
+	// Subscribe registers a handler function that is invoked whenever the given property changes. Returns a function
+	// that removes the subscription.
+	Subscribe(property PropertyName, handler ChangeHandlerFunc) func()
+
+	// SubscribeAll registers a handler function that is invoked whenever any property changes. Returns a function that
+	// removes the subscription.
+	SubscribeAll(handler ChangeHandlerFunc) func()
+
+	// Version returns a counter that is incremented every time a reload changes at least one property.
+	Version() uint64
+
+	// Watch polls the configuration sources at the given interval and reloads them whenever they change. Reload
+	// failures are passed to the error handler (if any) and leave the current values in place.
+	//
+	// This method BLOCKS until ctx.Done() is closed, so it should be run in its own goroutine.
+	Watch(ctx context.Context, interval time.Duration, onReloadError func(error)) error
+}
+
+// changeNotifier manages the change subscriptions of a configuration source. It also contains a mutex so it should
+// ONLY be passed around by-reference and never by-value.
+type changeNotifier struct {
+	allHandlers      map[uint64]ChangeHandlerFunc
+	mu               sync.Mutex
+	nextID           uint64
+	propertyHandlers map[PropertyName]map[uint64]ChangeHandlerFunc
+}
+
+// notify invokes the handlers subscribed to each of the changes. Handlers are invoked synchronously in the calling
+// goroutine, outside of the subscription lock.
+func (n *changeNotifier) notify(changes []PropertyChange) {
+	if n == nil {
+		return
+	}
+	for _, change := range changes {
+		n.mu.Lock()
+		handlers := []ChangeHandlerFunc{}
+		for _, handler := range n.propertyHandlers[change.Property] {
+			handlers = append(handlers, handler)
+		}
+		for _, handler := range n.allHandlers {
+			handlers = append(handlers, handler)
+		}
+		n.mu.Unlock()
+		for _, handler := range handlers {
+			handler(change)
+		}
+	}
+}
+
+// subscribe registers the handler for the given property, or for every property if the property is blank. Returns a
+// function that removes the subscription.
+func (n *changeNotifier) subscribe(property PropertyName, handler ChangeHandlerFunc) func() {
+	if n == nil || handler == nil {
+		return func() {}
+	}
+
+	// ensure we don't get a collision if two or more goroutines try to write concurrently
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.nextID++
+	id := n.nextID
+	if property == "" {
+		n.allHandlers[id] = handler
+	} else {
+		if _, exists := n.propertyHandlers[property]; !exists {
+			n.propertyHandlers[property] = map[uint64]ChangeHandlerFunc{}
+		}
+		n.propertyHandlers[property][id] = handler
+	}
+	return func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.allHandlers, id)
+		delete(n.propertyHandlers[property], id)
+	}
+}
+
+// newChangeNotifier returns a new change notifier struct instance without any subscriptions.
+func newChangeNotifier() *changeNotifier {
+	return &changeNotifier{
+		allHandlers:      map[uint64]ChangeHandlerFunc{},
+		propertyHandlers: map[PropertyName]map[uint64]ChangeHandlerFunc{},
+	}
+}
+
+// fileSnapshot describes the state of a watched file at a point in time.
+type fileSnapshot struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+// takeFileSnapshots returns the current state of each of the files.
+func takeFileSnapshots(paths []string) map[string]fileSnapshot {
+	snapshots := map[string]fileSnapshot{}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			snapshots[path] = fileSnapshot{}
+			continue
+		}
+		snapshots[path] = fileSnapshot{
+			exists:  true,
+			modTime: info.ModTime(),
+			size:    info.Size(),
+		}
+	}
+	return snapshots
+}
+
+// watchFiles polls the files returned by getPaths at the given interval and invokes onChange whenever any of them is
+// created, removed, or modified.
+//
+// This function BLOCKS until ctx.Done() is closed, so it should be run in its own goroutine.
+func watchFiles(ctx context.Context, interval time.Duration, getPaths func() []string, onChange func()) error {
+	if interval <= 0 {
+		return ErrInvalidWatchInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	previous := takeFileSnapshots(getPaths())
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			current := takeFileSnapshots(getPaths())
+			if !fileSnapshotsEqual(previous, current) {
+				onChange()
+			}
+			previous = current
+		}
+	}
+}
+
+// fileSnapshotsEqual returns whether both sets of snapshots describe the same files in the same state.
+func fileSnapshotsEqual(a map[string]fileSnapshot, b map[string]fileSnapshot) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, snapshotA := range a {
+		snapshotB, exists := b[path]
+		if !exists || snapshotA.exists != snapshotB.exists || snapshotA.size != snapshotB.size ||
+			!snapshotA.modTime.Equal(snapshotB.modTime) {
+			return false
+		}
+	}
+	return true
+}
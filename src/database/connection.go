@@ -1,6 +1,7 @@
 package database
 
 import (
+	"database/sql"
 	"fmt"
 
 	"gorm.io/gorm"
@@ -14,12 +15,16 @@ type DatabaseConnection struct {
 
 // DatabaseConnectionArguments is a struct representing the general properties expected when making a connection
 // to a database environment.
+//
+// The struct tags allow the arguments to be filled from the service configuration with config.Bind(). The password is
+// intentionally left untagged because it is resolved separately as a secret.
 type DatabaseConnectionArguments struct {
-	DatabaseName string
-	Host         string
+	DatabaseName string `config:"DATABASE_NAME" required:"true"`
+	Host         string `config:"DATABASE_HOST" required:"true"`
 	Password     string
-	Port         string
-	Username     string
+	Pool         DatabasePoolArguments
+	Port         string `config:"DATABASE_PORT"`
+	Username     string `config:"DATABASE_USERNAME" required:"true"`
 }
 
 // NewDatabaseConnection opens and initializes a database connection based upon the GORM dialector, a boolean
@@ -28,6 +33,19 @@ type DatabaseConnectionArguments struct {
 func NewDatabaseConnection(
 	gormConnection gorm.Dialector, shouldUseDebugMode bool, gormOptions ...gorm.Option,
 ) (*DatabaseConnection, error) {
+	return NewDatabaseConnectionWithPool(gormConnection, nil, shouldUseDebugMode, gormOptions...)
+}
+
+// NewDatabaseConnectionWithPool performs the same operation as NewDatabaseConnection but also applies the connection
+// pool settings to the underlying database handle. The pool arguments may be nil to keep the defaults of the
+// database/sql package. Returns the DB connection pointer as well as any error that may have occurred.
+func NewDatabaseConnectionWithPool(
+	gormConnection gorm.Dialector, poolArguments *DatabasePoolArguments, shouldUseDebugMode bool,
+	gormOptions ...gorm.Option,
+) (*DatabaseConnection, error) {
+	if err := ValidateDatabasePoolArguments(poolArguments); err != nil {
+		return nil, err
+	}
 	db, err := gorm.Open(gormConnection, gormOptions...)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrCannotOpenDatabaseConnection, err)
@@ -35,6 +53,13 @@ func NewDatabaseConnection(
 	if db == nil {
 		return nil, ErrNoDatabaseConnectionReturned
 	}
+	if poolArguments != nil {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrCannotOpenDatabaseConnection, err)
+		}
+		ConfigureDatabasePool(sqlDB, poolArguments)
+	}
 	if shouldUseDebugMode {
 		db = db.Debug()
 	}
@@ -52,6 +77,19 @@ func (dc *DatabaseConnection) GetGORMDB() *gorm.DB {
 	return dc.db
 }
 
+// GetPoolStats returns the statistics of the connection pool underlying this connection (e.g., the number of open,
+// in-use and idle connections). Returns the statistics plus any error that may have occurred.
+func (dc *DatabaseConnection) GetPoolStats() (sql.DBStats, error) {
+	if dc == nil || dc.db == nil {
+		return sql.DBStats{}, nil
+	}
+	sqlDB, err := dc.db.DB()
+	if err != nil {
+		return sql.DBStats{}, err
+	}
+	return sqlDB.Stats(), nil
+}
+
 // IsUsingDebugMode returns a boolean describing whether "debug mode" is turned on for this connection.
 func (dc *DatabaseConnection) IsUsingDebugMode() bool {
 	if dc == nil {
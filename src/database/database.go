@@ -1,6 +1,8 @@
 package database
 
 import (
+	"database/sql"
+
 	"gorm.io/gorm"
 )
 
@@ -9,6 +11,10 @@ type Contract interface {
 	// GetGORMDB returns the GORM DB pointer for the connection.
 	GetGORMDB() *gorm.DB
 
+	// GetPoolStats returns the statistics of the connection pool underlying the connection (e.g., for health checks
+	// and metrics).
+	GetPoolStats() (sql.DBStats, error)
+
 	// IsUsingDebugMode returns a boolean describing whether "debug mode" is turned on for the connection.
 	IsUsingDebugMode() bool
 }
@@ -24,3 +24,37 @@ var ErrPostgresNoConnectionDatabaseName = errors.New("database name in connectio
 // ErrPostgresNoConnectionUsername is a sentinel error representing a blank username string when attempting to make a
 // Postgres DB connection.
 var ErrPostgresNoConnectionUsername = errors.New("username in connection arguments cannot be blank")
+
+// ErrDatabaseConnectionCannotBeNil is a sentinel error representing an attempt to use a nil database connection.
+var ErrDatabaseConnectionCannotBeNil = errors.New("database connection cannot be nil")
+
+// ErrInvalidMigration is a sentinel error representing a migration that is malformed, such as a script whose file
+// name does not follow the "<version>_<name>.<up|down>.sql" convention or a version that is used more than once.
+var ErrInvalidMigration = errors.New("invalid database migration")
+
+// ErrInvalidMigrationTableName is a sentinel error representing a schema history table name that is not a plain SQL
+// identifier.
+var ErrInvalidMigrationTableName = errors.New("invalid database migration table name")
+
+// ErrMigrationChecksumMismatch is a sentinel error representing an applied migration whose script has been changed
+// since it was applied.
+var ErrMigrationChecksumMismatch = errors.New("database migration has changed since it was applied")
+
+// ErrMigrationFailed is a sentinel error describing a failure to apply or roll back a migration.
+var ErrMigrationFailed = errors.New("cannot run database migration")
+
+// ErrMigrationLockFailed is a sentinel error describing a failure to acquire or release the lock that prevents more
+// than one instance from migrating at a time.
+var ErrMigrationLockFailed = errors.New("cannot lock database for migration")
+
+// ErrMigrationNoDownScript is a sentinel error representing an attempt to roll back a migration that has no down
+// script.
+var ErrMigrationNoDownScript = errors.New("database migration has no down script")
+
+// ErrMigrationNotFound is a sentinel error representing an attempt to roll back an applied migration whose scripts
+// cannot be found.
+var ErrMigrationNotFound = errors.New("database migration not found")
+
+// ErrInvalidPoolArguments is a sentinel error representing a negative connection pool setting when attempting to
+// make a database connection.
+var ErrInvalidPoolArguments = errors.New("connection pool settings in connection arguments cannot be negative")
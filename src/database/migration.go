@@ -0,0 +1,470 @@
+package database
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"math"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultMigrationLockID is the key of the Postgres advisory lock held while migrating when no other key has been
+	// provided.
+	DefaultMigrationLockID int64 = 4_613_280_245_171_917_101
+
+	// DefaultMigrationTableName is the name of the schema history table when no other name has been provided.
+	DefaultMigrationTableName = "schema_migrations"
+)
+
+// MigrationState represents the state of a migration within the schema history.
+type MigrationState string
+
+const (
+	// MigrationStateApplied describes a migration that has been applied and has not changed since.
+	MigrationStateApplied MigrationState = "applied"
+
+	// MigrationStateMissing describes a migration that has been applied but whose scripts cannot be found (e.g., it
+	// was applied by a newer release).
+	MigrationStateMissing MigrationState = "missing"
+
+	// MigrationStateModified describes a migration whose up script has changed since it was applied.
+	MigrationStateModified MigrationState = "modified"
+
+	// MigrationStatePending describes a migration that has not been applied yet.
+	MigrationStatePending MigrationState = "pending"
+)
+
+// migrationFileNamePattern matches the file name of a migration script (e.g., "0001_create_users.up.sql").
+var migrationFileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// migrationTableNamePattern matches the plain SQL identifiers accepted as schema history table names.
+var migrationTableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Migration is a struct representing a versioned change to the database schema.
+type Migration struct {
+	// Checksum is the hex-encoded SHA-256 hash of the up script, which is recorded when the migration is applied so
+	// that later changes to the script can be detected.
+	Checksum string
+
+	// Down is the SQL script that reverts the migration. It may be blank if the migration cannot be rolled back.
+	Down string
+
+	// Name describes the migration (e.g., "create_users").
+	Name string
+
+	// Up is the SQL script that applies the migration.
+	Up string
+
+	// Version orders the migrations; it must be positive and unique.
+	Version int64
+}
+
+// MigrationStatus is a struct representing the state of a single migration within the schema history.
+type MigrationStatus struct {
+	// AppliedAt is when the migration was applied, or the zero time if it is pending.
+	AppliedAt time.Time
+
+	// Name describes the migration.
+	Name string
+
+	// State describes whether the migration has been applied.
+	State MigrationState
+
+	// Version is the version of the migration.
+	Version int64
+}
+
+// MigratorOptions is a struct representing the optional behaviour of a Migrator.
+type MigratorOptions struct {
+	// DryRun reports the migrations that would be applied or rolled back without changing the database.
+	DryRun bool
+
+	// LockID is the key of the Postgres advisory lock held while migrating. Defaults to DefaultMigrationLockID.
+	LockID int64
+
+	// TableName is the name of the schema history table. Defaults to DefaultMigrationTableName.
+	TableName string
+}
+
+// Migrator applies and rolls back versioned migrations, recording every applied migration and the checksum of its up
+// script within the schema history table.
+//
+// Every migration runs within its own transaction along with its schema history record. On Postgres, a session-level
+// advisory lock is held while migrating so that only one instance migrates at a time; the others wait for it and then
+// find nothing left to do.
+type Migrator struct {
+	db         *gorm.DB
+	dryRun     bool
+	lockID     int64
+	migrations []Migration
+	tableName  string
+}
+
+// appliedMigration is a struct representing a row of the schema history table.
+type appliedMigration struct {
+	AppliedAt time.Time
+	Checksum  string
+	Name      string
+	Version   int64
+}
+
+// Down rolls back up to the given number of the most recently applied migrations, newest first. In dry-run mode, the
+// migrations that would be rolled back are returned without changing the database. Returns the migrations that were
+// rolled back plus any error that may have occurred.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if m == nil {
+		return nil, nil
+	}
+	rolledBack := []Migration{}
+	err := m.withLock(ctx, func(db *gorm.DB) error {
+		applied, err := m.readAppliedMigrations(db)
+		if err != nil {
+			return err
+		}
+		if err = m.verifyChecksums(applied); err != nil {
+			return err
+		}
+		versions := make([]int64, 0, len(applied))
+		for version := range applied {
+			versions = append(versions, version)
+		}
+		slices.Sort(versions)
+		slices.Reverse(versions)
+		for _, version := range versions[:min(max(steps, 0), len(versions))] {
+			migration, exists := m.findMigration(version)
+			if !exists {
+				return fmt.Errorf("%w: %d_%s", ErrMigrationNotFound, version, applied[version].Name)
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("%w: %d_%s", ErrMigrationNoDownScript, version, migration.Name)
+			}
+			if !m.dryRun {
+				if err = m.runMigration(db, migration, false); err != nil {
+					return err
+				}
+			}
+			rolledBack = append(rolledBack, migration)
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// Migrations returns a copy of the migrations known to this migrator, ordered by version.
+func (m *Migrator) Migrations() []Migration {
+	if m == nil {
+		return []Migration{}
+	}
+	return slices.Clone(m.migrations)
+}
+
+// Status returns the state of every known or applied migration, ordered by version. It does not change the database.
+// Returns the statuses plus any error that may have occurred.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if m == nil {
+		return []MigrationStatus{}, nil
+	}
+	applied, err := m.readAppliedMigrations(m.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]MigrationStatus, 0, len(m.migrations)+len(applied))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Name: migration.Name, State: MigrationStatePending, Version: migration.Version}
+		if record, exists := applied[migration.Version]; exists {
+			status.AppliedAt = record.AppliedAt
+			status.State = MigrationStateApplied
+			if record.Checksum != migration.Checksum {
+				status.State = MigrationStateModified
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	for version, record := range applied {
+		if _, exists := m.findMigration(version); !exists {
+			statuses = append(statuses, MigrationStatus{
+				AppliedAt: record.AppliedAt,
+				Name:      record.Name,
+				State:     MigrationStateMissing,
+				Version:   version,
+			})
+		}
+	}
+	slices.SortFunc(statuses, func(a, b MigrationStatus) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return statuses, nil
+}
+
+// Up applies every pending migration in version order. In dry-run mode, the migrations that would be applied are
+// returned without changing the database. Returns the migrations that were applied plus any error that may have
+// occurred.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	return m.UpTo(ctx, math.MaxInt64)
+}
+
+// UpTo applies every pending migration up to and including the given version in version order. Pending migrations
+// older than the latest applied migration (e.g., from a branch merged late) are applied as well. Nothing is applied
+// if any applied migration has changed since it was applied. Returns the migrations that were applied plus any error
+// that may have occurred.
+func (m *Migrator) UpTo(ctx context.Context, version int64) ([]Migration, error) {
+	if m == nil {
+		return nil, nil
+	}
+	migrated := []Migration{}
+	err := m.withLock(ctx, func(db *gorm.DB) error {
+		if !m.dryRun {
+			if err := m.createTable(db); err != nil {
+				return err
+			}
+		}
+		applied, err := m.readAppliedMigrations(db)
+		if err != nil {
+			return err
+		}
+		if err = m.verifyChecksums(applied); err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if _, exists := applied[migration.Version]; exists || migration.Version > version {
+				continue
+			}
+			if !m.dryRun {
+				if err = m.runMigration(db, migration, true); err != nil {
+					return err
+				}
+			}
+			migrated = append(migrated, migration)
+		}
+		return nil
+	})
+	return migrated, err
+}
+
+// createTable creates the schema history table if it does not exist yet. Returns any error that may have occurred.
+func (m *Migrator) createTable(db *gorm.DB) error {
+	err := db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	version BIGINT PRIMARY KEY,
+	name TEXT NOT NULL,
+	checksum TEXT NOT NULL,
+	applied_at TIMESTAMP WITH TIME ZONE NOT NULL
+)`, m.tableName)).Error
+	if err != nil {
+		return fmt.Errorf("%w: cannot create %s: %w", ErrMigrationFailed, m.tableName, err)
+	}
+	return nil
+}
+
+// findMigration returns the migration with the given version plus a boolean describing whether it exists.
+func (m *Migrator) findMigration(version int64) (Migration, bool) {
+	index, found := slices.BinarySearchFunc(m.migrations, version, func(migration Migration, version int64) int {
+		return cmp.Compare(migration.Version, version)
+	})
+	if !found {
+		return Migration{}, false
+	}
+	return m.migrations[index], true
+}
+
+// readAppliedMigrations returns the rows of the schema history table keyed by version, or no rows if the table does
+// not exist yet. Returns the rows plus any error that may have occurred.
+func (m *Migrator) readAppliedMigrations(db *gorm.DB) (map[int64]appliedMigration, error) {
+	applied := map[int64]appliedMigration{}
+	records := []appliedMigration{}
+	if err := db.Table(m.tableName).Find(&records).Error; err != nil {
+		// the table is only checked for after a failure, since a failed check cannot be told apart from a missing table
+		if !db.Migrator().HasTable(m.tableName) {
+			return applied, nil
+		}
+		return nil, fmt.Errorf("%w: cannot read %s: %w", ErrMigrationFailed, m.tableName, err)
+	}
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// runMigration applies (or rolls back) the migration and records (or removes) it within the schema history table in
+// a single transaction. Returns any error that may have occurred.
+func (m *Migrator) runMigration(db *gorm.DB, migration Migration, isUp bool) error {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if isUp {
+			if err := tx.Exec(migration.Up).Error; err != nil {
+				return err
+			}
+			return tx.Exec(fmt.Sprintf("INSERT INTO %s (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)",
+				m.tableName), migration.Version, migration.Name, migration.Checksum, time.Now().UTC()).Error
+		}
+		if err := tx.Exec(migration.Down).Error; err != nil {
+			return err
+		}
+		return tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE version = ?", m.tableName), migration.Version).Error
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %d_%s: %w", ErrMigrationFailed, migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+// verifyChecksums returns ErrMigrationChecksumMismatch if the up script of any applied migration has changed since
+// it was applied.
+func (m *Migrator) verifyChecksums(applied map[int64]appliedMigration) error {
+	for _, migration := range m.migrations {
+		if record, exists := applied[migration.Version]; exists && record.Checksum != migration.Checksum {
+			return fmt.Errorf("%w: %d_%s", ErrMigrationChecksumMismatch, migration.Version, migration.Name)
+		}
+	}
+	return nil
+}
+
+// withLock runs the function on a single connection while holding the advisory lock on Postgres. The lock is skipped
+// in dry-run mode and on other databases. Returns any error that may have occurred.
+func (m *Migrator) withLock(ctx context.Context, fn func(db *gorm.DB) error) error {
+	if m.dryRun || m.db.Dialector.Name() != "postgres" {
+		return fn(m.db.WithContext(ctx))
+	}
+	return m.db.WithContext(ctx).Connection(func(db *gorm.DB) error {
+		if err := db.Exec("SELECT pg_advisory_lock(?)", m.lockID).Error; err != nil {
+			return fmt.Errorf("%w: %w", ErrMigrationLockFailed, err)
+		}
+		err := fn(db)
+
+		// the lock belongs to the session so it must be released even if the context is done, otherwise the pooled
+		// connection would keep holding it
+		unlockErr := db.WithContext(context.WithoutCancel(ctx)).Exec("SELECT pg_advisory_unlock(?)", m.lockID).Error
+		if err == nil && unlockErr != nil {
+			return fmt.Errorf("%w: %w", ErrMigrationLockFailed, unlockErr)
+		}
+		return err
+	})
+}
+
+// LoadMigrations reads the migration scripts from the directory of the file system (e.g., an embed.FS). Each migration
+// is a "<version>_<name>.up.sql" script plus an optional "<version>_<name>.down.sql" script (e.g.,
+// "0001_create_users.up.sql"); other files are ignored. Returns the migrations ordered by version plus any error that
+// may have occurred.
+func LoadMigrations(fileSystem fs.FS, directory string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fileSystem, directory)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMigration, err)
+	}
+	migrationsByVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		matches := migrationFileNamePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("%w: file name must follow <version>_<name>.<up|down>.sql: %s", ErrInvalidMigration,
+				entry.Name())
+		}
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("%w: version must be a positive integer: %s", ErrInvalidMigration, entry.Name())
+		}
+		script, err := fs.ReadFile(fileSystem, path.Join(directory, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidMigration, err)
+		}
+		migration, exists := migrationsByVersion[version]
+		if !exists {
+			migration = &Migration{Name: matches[2], Version: version}
+			migrationsByVersion[version] = migration
+		}
+		if migration.Name != matches[2] {
+			return nil, fmt.Errorf("%w: version %d is used by both %s and %s", ErrInvalidMigration, version,
+				migration.Name, matches[2])
+		}
+		if matches[3] == "up" {
+			migration.Up = string(script)
+			migration.Checksum = MakeMigrationChecksum(migration.Up)
+		} else {
+			migration.Down = string(script)
+		}
+	}
+	migrations := make([]Migration, 0, len(migrationsByVersion))
+	for _, migration := range migrationsByVersion {
+		if migration.Checksum == "" {
+			return nil, fmt.Errorf("%w: %d_%s has no up script", ErrInvalidMigration, migration.Version,
+				migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return migrations, nil
+}
+
+// MakeMigrationChecksum returns the hex-encoded SHA-256 hash of the up script of a migration.
+func MakeMigrationChecksum(script string) string {
+	checksum := sha256.Sum256([]byte(script))
+	return hex.EncodeToString(checksum[:])
+}
+
+// NewMigrator returns a new migrator for the migrations on the database connection. Migrations without a checksum
+// have it computed from their up script. The options may be nil. Returns the migrator plus any error that may have
+// occurred.
+func NewMigrator(connection Contract, migrations []Migration, options *MigratorOptions) (*Migrator, error) {
+	if connection == nil || connection.GetGORMDB() == nil {
+		return nil, ErrDatabaseConnectionCannotBeNil
+	}
+	migrator := &Migrator{
+		db:         connection.GetGORMDB(),
+		lockID:     DefaultMigrationLockID,
+		migrations: slices.Clone(migrations),
+		tableName:  DefaultMigrationTableName,
+	}
+	if options != nil {
+		migrator.dryRun = options.DryRun
+		if options.LockID != 0 {
+			migrator.lockID = options.LockID
+		}
+		if options.TableName != "" {
+			migrator.tableName = options.TableName
+		}
+	}
+	if !migrationTableNamePattern.MatchString(migrator.tableName) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidMigrationTableName, migrator.tableName)
+	}
+	slices.SortFunc(migrator.migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	for i, migration := range migrator.migrations {
+		if migration.Version <= 0 {
+			return nil, fmt.Errorf("%w: version must be positive: %d_%s", ErrInvalidMigration, migration.Version,
+				migration.Name)
+		}
+		if i > 0 && migrator.migrations[i-1].Version == migration.Version {
+			return nil, fmt.Errorf("%w: version %d is used more than once", ErrInvalidMigration, migration.Version)
+		}
+		if migration.Checksum == "" {
+			migrator.migrations[i].Checksum = MakeMigrationChecksum(migration.Up)
+		}
+	}
+	return migrator, nil
+}
+
+// NewMigratorFromFS returns a new migrator for the migration scripts read from the directory of the file system (see
+// LoadMigrations) on the database connection. The options may be nil. Returns the migrator plus any error that may
+// have occurred.
+func NewMigratorFromFS(
+	connection Contract, fileSystem fs.FS, directory string, options *MigratorOptions,
+) (*Migrator, error) {
+	migrations, err := LoadMigrations(fileSystem, directory)
+	if err != nil {
+		return nil, err
+	}
+	return NewMigrator(connection, migrations, options)
+}
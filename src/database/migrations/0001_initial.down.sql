@@ -0,0 +1,2 @@
+-- The baseline has nothing to revert.
+SELECT 1;
@@ -0,0 +1,3 @@
+-- Baseline of the database schema. Add tables and other changes within new migrations that follow this one (e.g.,
+-- 0002_create_users.up.sql and 0002_create_users.down.sql).
+SELECT 1;
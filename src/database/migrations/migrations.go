@@ -0,0 +1,10 @@
+package migrations
+
+import "embed"
+
+// FS holds the SQL migrations of the service's database schema, which are compiled into the binary. Each migration is
+// a "<version>_<name>.up.sql" script plus an optional "<version>_<name>.down.sql" script that reverts it; scripts must
+// never be changed once they have been applied anywhere, so add a new migration instead.
+//
+//go:embed *.sql
+var FS embed.FS
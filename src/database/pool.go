@@ -0,0 +1,154 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// MetricNamePoolStats is the prefix of every metric reported by the connection pool statistics collector (e.g.,
+// "database_pool_open_connections").
+const MetricNamePoolStats = "database_pool"
+
+// DatabasePoolArguments is a struct representing the connection pool settings of a database connection. A zero value
+// keeps the default of the database/sql package for that setting.
+type DatabasePoolArguments struct {
+	// ConnMaxIdleTime is the longest time a connection may sit idle in the pool before it is closed.
+	ConnMaxIdleTime time.Duration `config:"DATABASE_CONN_MAX_IDLE_TIME"`
+
+	// ConnMaxLifetime is the longest time a connection may be reused before it is closed (e.g., so that connections
+	// move to new database replicas or proxies).
+	ConnMaxLifetime time.Duration `config:"DATABASE_CONN_MAX_LIFETIME"`
+
+	// MaxIdleConns is the maximum number of idle connections kept in the pool.
+	MaxIdleConns int `config:"DATABASE_MAX_IDLE_CONNS"`
+
+	// MaxOpenConns is the maximum number of connections open at once, in use or idle.
+	MaxOpenConns int `config:"DATABASE_MAX_OPEN_CONNS"`
+}
+
+// PoolStatsCollector is a metrics collector that reports the connection pool statistics of a database connection as
+// gauges (open, in-use and idle connections plus the limit) and counters (waits for a connection and connections
+// closed by each limit), labelled with the name of the database.
+type PoolStatsCollector struct {
+	connection Contract
+	name       string
+}
+
+// poolStatsMetric describes a single metric reported by the PoolStatsCollector.
+type poolStatsMetric struct {
+	description *prometheus.Desc
+	value       func(stats sql.DBStats) float64
+	valueType   prometheus.ValueType
+}
+
+// poolStatsMetrics are the metrics reported by every PoolStatsCollector, sorted by name.
+var poolStatsMetrics = []poolStatsMetric{
+	newPoolStatsMetric("idle_connections", "Number of idle connections.", prometheus.GaugeValue,
+		func(stats sql.DBStats) float64 { return float64(stats.Idle) }),
+	newPoolStatsMetric("in_use_connections", "Number of connections in use.", prometheus.GaugeValue,
+		func(stats sql.DBStats) float64 { return float64(stats.InUse) }),
+	newPoolStatsMetric("max_idle_closed_total", "Number of connections closed because of the idle limit.",
+		prometheus.CounterValue, func(stats sql.DBStats) float64 { return float64(stats.MaxIdleClosed) }),
+	newPoolStatsMetric("max_idle_time_closed_total", "Number of connections closed because of the idle time limit.",
+		prometheus.CounterValue, func(stats sql.DBStats) float64 { return float64(stats.MaxIdleTimeClosed) }),
+	newPoolStatsMetric("max_lifetime_closed_total", "Number of connections closed because of the lifetime limit.",
+		prometheus.CounterValue, func(stats sql.DBStats) float64 { return float64(stats.MaxLifetimeClosed) }),
+	newPoolStatsMetric("max_open_connections", "Maximum number of open connections; zero means unlimited.",
+		prometheus.GaugeValue, func(stats sql.DBStats) float64 { return float64(stats.MaxOpenConnections) }),
+	newPoolStatsMetric("open_connections", "Number of open connections, in use or idle.", prometheus.GaugeValue,
+		func(stats sql.DBStats) float64 { return float64(stats.OpenConnections) }),
+	newPoolStatsMetric("wait_count_total", "Number of times a connection had to be waited for.",
+		prometheus.CounterValue, func(stats sql.DBStats) float64 { return float64(stats.WaitCount) }),
+	newPoolStatsMetric("wait_duration_seconds_total", "Total time spent waiting for a connection in seconds.",
+		prometheus.CounterValue, func(stats sql.DBStats) float64 { return stats.WaitDuration.Seconds() }),
+}
+
+// Collect sends the current connection pool statistics to the channel. If the statistics cannot be read, an invalid
+// metric carrying the error is sent instead so that the scrape reports it.
+func (c *PoolStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	if c == nil || c.connection == nil {
+		return
+	}
+	stats, err := c.connection.GetPoolStats()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(poolStatsMetrics[0].description, err)
+		return
+	}
+	for _, metric := range poolStatsMetrics {
+		ch <- prometheus.MustNewConstMetric(metric.description, metric.valueType, metric.value(stats), c.name)
+	}
+}
+
+// Describe sends the descriptions of every connection pool metric to the channel.
+func (c *PoolStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range poolStatsMetrics {
+		ch <- metric.description
+	}
+}
+
+// ConfigureDatabasePool applies the connection pool settings to the database handle, keeping the current value of
+// every setting that is zero.
+func ConfigureDatabasePool(sqlDB *sql.DB, poolArguments *DatabasePoolArguments) {
+	if sqlDB == nil || poolArguments == nil {
+		return
+	}
+	if poolArguments.ConnMaxIdleTime > 0 {
+		sqlDB.SetConnMaxIdleTime(poolArguments.ConnMaxIdleTime)
+	}
+	if poolArguments.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(poolArguments.ConnMaxLifetime)
+	}
+	if poolArguments.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(poolArguments.MaxIdleConns)
+	}
+	if poolArguments.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(poolArguments.MaxOpenConns)
+	}
+}
+
+// NewPoolStatsCollector returns a new metrics collector for the connection pool statistics of the database
+// connection, labelled with the name (e.g., the database driver). Returns the collector plus any error that may have
+// occurred.
+func NewPoolStatsCollector(connection Contract, name string) (*PoolStatsCollector, error) {
+	if connection == nil {
+		return nil, ErrDatabaseConnectionCannotBeNil
+	}
+	return &PoolStatsCollector{
+		connection: connection,
+		name:       name,
+	}, nil
+}
+
+// ValidateDatabasePoolArguments takes a DatabasePoolArguments struct pointer and returns ErrInvalidPoolArguments if
+// any of the settings are negative. Returns nil if the validation checks pass, including for a nil pointer.
+func ValidateDatabasePoolArguments(poolArguments *DatabasePoolArguments) error {
+	if poolArguments == nil {
+		return nil
+	}
+	switch {
+	case poolArguments.ConnMaxIdleTime < 0:
+		return fmt.Errorf("%w: maximum idle time %s", ErrInvalidPoolArguments, poolArguments.ConnMaxIdleTime)
+	case poolArguments.ConnMaxLifetime < 0:
+		return fmt.Errorf("%w: maximum lifetime %s", ErrInvalidPoolArguments, poolArguments.ConnMaxLifetime)
+	case poolArguments.MaxIdleConns < 0:
+		return fmt.Errorf("%w: maximum idle connections %d", ErrInvalidPoolArguments, poolArguments.MaxIdleConns)
+	case poolArguments.MaxOpenConns < 0:
+		return fmt.Errorf("%w: maximum open connections %d", ErrInvalidPoolArguments, poolArguments.MaxOpenConns)
+	}
+	return nil
+}
+
+// newPoolStatsMetric returns the description of a connection pool metric whose name is appended to
+// MetricNamePoolStats.
+func newPoolStatsMetric(
+	name string, help string, valueType prometheus.ValueType, value func(stats sql.DBStats) float64,
+) poolStatsMetric {
+	return poolStatsMetric{
+		description: prometheus.NewDesc(MetricNamePoolStats+"_"+name, help, []string{"database"}, nil),
+		value:       value,
+		valueType:   valueType,
+	}
+}
@@ -13,8 +13,8 @@ import (
 type PostgresDatabaseConnectionArguments struct {
 	DatabaseConnectionArguments
 
-	SSLMode  string
-	Timezone string
+	SSLMode  string `config:"DATABASE_SSL_MODE"`
+	Timezone string `config:"DATABASE_TIMEZONE"`
 }
 
 // MakePostgresConfigFromDSN takes a DSN string and returns a postgres.Config instance that contains it.
@@ -66,7 +66,8 @@ func NewPostgresDatabaseConnection(
 	postgresDSN := MakePostgresDSNFromConnectionArguments(connectionArguments)
 	postgresConfig := MakePostgresConfigFromDSN(postgresDSN)
 	postgresDialector := MakePostgresDialectorFromConfig(postgresConfig)
-	return NewDatabaseConnection(postgresDialector, shouldUseDebugMode, gormOptions...)
+	return NewDatabaseConnectionWithPool(postgresDialector, &connectionArguments.Pool, shouldUseDebugMode,
+		gormOptions...)
 }
 
 // ValidatePostgresConnectionArguments takes a PostgresDatabaseConnectionArguments struct pointer and returns an error
@@ -11,9 +11,10 @@ import (
 
 // HTTPClient represents a struct that provides basic HTTP client connectivity capabilities.
 type HTTPClient struct {
-	client  *http.Client
-	headers map[string]string
-	mu      sync.Mutex
+	client      *http.Client
+	headers     map[string]string
+	mu          sync.Mutex
+	rateLimiter RateLimiterContract
 }
 
 // MakeDefaultNetHTTPClient builds and returns a new default http.Client pointer instance that can be used immediately
@@ -89,11 +90,26 @@ func (hc *HTTPClient) SendWithHeaders(
 func (hc *HTTPClient) SendWithHeadersAndBody(
 	ctx context.Context, method HTTPMethod, url string, overrideHeaders map[string]string, body []byte,
 ) ([]byte, error) {
+	_, respBody, err := hc.send(ctx, method, url, overrideHeaders, body)
+	return respBody, err
+}
+
+// SendWithStatus performs the same operation as Send but also returns the status code of the response, or zero if no
+// response was received.
+func (hc *HTTPClient) SendWithStatus(ctx context.Context, method HTTPMethod, url string) (int, []byte, error) {
+	return hc.send(ctx, method, url, MakeEmptyRequestHeaders(), MakeEmptyRequestBody())
+}
+
+// send sends the request and returns the status code of the response, or zero if no response was received, along with
+// the response body and any error that may have occurred.
+func (hc *HTTPClient) send(
+	ctx context.Context, method HTTPMethod, url string, overrideHeaders map[string]string, body []byte,
+) (int, []byte, error) {
 	if hc == nil {
-		return []byte{}, ErrHTTPClientCannotBeNil
+		return 0, []byte{}, ErrHTTPClientCannotBeNil
 	}
 	if hc.client == nil {
-		return []byte{}, ErrNetHTTPClientCannotBeNil
+		return 0, []byte{}, ErrNetHTTPClientCannotBeNil
 	}
 	if body == nil {
 		body = []byte{}
@@ -102,7 +118,7 @@ func (hc *HTTPClient) SendWithHeadersAndBody(
 	// build the request based on the passed context
 	req, err := http.NewRequestWithContext(ctx, method.String(), url, bytes.NewReader(body))
 	if err != nil {
-		return []byte{}, fmt.Errorf("%w: %w", ErrCannotCreateHTTPRequest, err)
+		return 0, []byte{}, fmt.Errorf("%w: %w", ErrCannotCreateHTTPRequest, err)
 	}
 
 	// force the default headers from the map if we have any
@@ -119,19 +135,29 @@ func (hc *HTTPClient) SendWithHeadersAndBody(
 		}
 	}
 
+	// wait for the rate limiter (if any) to allow a request to this host
+	hc.mu.Lock()
+	rateLimiter := hc.rateLimiter
+	hc.mu.Unlock()
+	if rateLimiter != nil {
+		if err = rateLimiter.Wait(ctx, req.URL.Host); err != nil {
+			return 0, []byte{}, fmt.Errorf("%w: %w", ErrHTTPRequestRateLimited, err)
+		}
+	}
+
 	// send the request
 	resp, err := hc.client.Do(req)
 	if err != nil {
-		return []byte{}, fmt.Errorf("%w: %w", ErrHTTPRequestFailed, err)
+		return 0, []byte{}, fmt.Errorf("%w: %w", ErrHTTPRequestFailed, err)
 	}
 
 	// read the response
 	defer resp.Body.Close()
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return []byte{}, fmt.Errorf("%w: %w", ErrCannotReadHTTPResponseBody, err)
+		return resp.StatusCode, []byte{}, fmt.Errorf("%w: %w", ErrCannotReadHTTPResponseBody, err)
 	}
-	return respBody, nil
+	return resp.StatusCode, respBody, nil
 }
 
 // GetDefaultHeaders returns a map containing the headers that are included with every request by default.
@@ -165,3 +191,15 @@ func (hc *HTTPClient) SetDefaultHeaders(headers map[string]string) {
 	}
 	hc.headers = newHeaders
 }
+
+// SetRateLimiter allows you to set a rate limiter that every request waits on before it is sent, keyed by the host of
+// the request URL (e.g., "api.example.com:443"). A nil rate limiter turns rate limiting off.
+func (hc *HTTPClient) SetRateLimiter(rateLimiter RateLimiterContract) {
+	if hc == nil {
+		return
+	}
+	// ensure we don't get a collision if two or more goroutines try to write concurrently
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	hc.rateLimiter = rateLimiter
+}
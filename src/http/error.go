@@ -14,5 +14,9 @@ var ErrHTTPClientCannotBeNil = errors.New("HTTPClient instance cannot be nil")
 // ErrHTTPRequestFailed is a sentinel error representing a failure when sending an HTTP request itself.
 var ErrHTTPRequestFailed = errors.New("http request failed")
 
+// ErrHTTPRequestRateLimited is a sentinel error representing an HTTP request that was not sent because the rate
+// limiter did not allow it in time.
+var ErrHTTPRequestRateLimited = errors.New("http request rate limited")
+
 // ErrNetHTTPClientCannotBeNil is a sentinel error representing an attempt to use a nil http.Client pointer.
 var ErrNetHTTPClientCannotBeNil = errors.New("http.Client instance cannot be nil")
@@ -29,3 +29,21 @@ type Contract interface {
 	// SetDefaultHeaders allows you to set the headers that will be included with every request by default.
 	SetDefaultHeaders(headers map[string]string)
 }
+
+// StatusContract is an interface that represents a client used for performing HTTP operations that can also report
+// the status code of each response.
+type StatusContract interface {
+	Contract
+
+	// SendWithStatus performs the same operation as Send but also returns the status code of the response, or zero if
+	// no response was received.
+	SendWithStatus(ctx context.Context, method HTTPMethod, url string) (int, []byte, error)
+}
+
+// RateLimiterContract is an interface that represents a rate limiter an HTTP client can wait on before sending each
+// request.
+type RateLimiterContract interface {
+	// Wait blocks until a request for the key fits within its quota or until the context is done, in which case an
+	// error is returned.
+	Wait(ctx context.Context, key string) error
+}
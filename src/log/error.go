@@ -10,3 +10,7 @@ var ErrCannotCreateLogger = errors.New("cannot create logger")
 
 // ErrZapLoggerCannotBeNil is a sentinel error representing an attempt to use a nil zap-based logger pointer.
 var ErrZapLoggerCannotBeNil = errors.New("zap logger instance cannot be nil")
+
+// ErrLevelNotAdjustable is a sentinel error representing an attempt to change the level of a logger that was not
+// created with an adjustable level.
+var ErrLevelNotAdjustable = errors.New("logger level is not adjustable")
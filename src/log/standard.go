@@ -11,6 +11,7 @@ import (
 type StandardLogger struct {
 	debugLogger DebugContract
 	debugMode   bool
+	level       *zap.AtomicLevel
 	logger      *zap.Logger
 }
 
@@ -78,6 +79,17 @@ func (l *StandardLogger) IsUsingDebugMode() bool {
 	return l.debugMode
 }
 
+// Level returns the minimum level at which messages are currently logged.
+func (l *StandardLogger) Level() zapcore.Level {
+	if l == nil || l.logger == nil {
+		return zapcore.InvalidLevel
+	}
+	if l.level == nil {
+		return zapcore.LevelOf(l.logger.Core())
+	}
+	return l.level.Level()
+}
+
 // Log logs a message at the specified level with the specified fields.
 func (l *StandardLogger) Log(lvl zapcore.Level, msg string, fields ...zap.Field) {
 	if l == nil || l.logger == nil {
@@ -94,6 +106,16 @@ func (l *StandardLogger) Panic(msg string, fields ...zap.Field) {
 	l.logger.Panic(msg, fields...)
 }
 
+// SetLevel changes the minimum level at which messages are logged while the logger is in use. Returns an error if
+// the logger was not created by NewStandardLogger and therefore has no adjustable level.
+func (l *StandardLogger) SetLevel(lvl zapcore.Level) error {
+	if l == nil || l.level == nil {
+		return ErrLevelNotAdjustable
+	}
+	l.level.SetLevel(lvl)
+	return nil
+}
+
 // Sync flushes any buffered log entries. This should be called before application exit.
 func (l *StandardLogger) Sync() error {
 	if l == nil || l.logger == nil {
@@ -141,6 +163,7 @@ func (l *StandardLogger) WithDebugLogger(debugLogger DebugContract) *StandardLog
 	return &StandardLogger{
 		debugLogger: debugLogger,
 		debugMode:   l.debugMode,
+		level:       l.level,
 		logger:      l.logger.WithOptions(),
 	}
 }
@@ -154,6 +177,7 @@ func (l *StandardLogger) WithOptions(opts ...zap.Option) *StandardLogger {
 	return &StandardLogger{
 		debugLogger: l.debugLogger,
 		debugMode:   l.debugMode,
+		level:       l.level,
 		logger:      logger,
 	}
 }
@@ -161,14 +185,16 @@ func (l *StandardLogger) WithOptions(opts ...zap.Option) *StandardLogger {
 // NewStandardLogger takes a boolean describing whether "debug / development mode" should be turned on, plus a set of
 // zap options, and returns a new StandardLogger instance as well as any error that may have occurred when attempting
 // to createsaid logger.
+//
+// The level of the returned logger can be adjusted while it is in use with SetLevel().
 func NewStandardLogger(shouldUseDebugMode bool, options ...zap.Option) (*StandardLogger, error) {
-	var logger *zap.Logger
-	var err error
+	var zapConfig zap.Config
 	if shouldUseDebugMode {
-		logger, err = zap.NewDevelopment(options...)
+		zapConfig = zap.NewDevelopmentConfig()
 	} else {
-		logger, err = zap.NewProduction(options...)
+		zapConfig = zap.NewProductionConfig()
 	}
+	logger, err := zapConfig.Build(options...)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrCannotCreateLogger, err)
 	}
@@ -179,6 +205,7 @@ func NewStandardLogger(shouldUseDebugMode bool, options ...zap.Option) (*Standar
 	return &StandardLogger{
 		debugLogger: debugLogger,
 		debugMode:   shouldUseDebugMode,
+		level:       &zapConfig.Level,
 		logger:      logger,
 	}, nil
 }
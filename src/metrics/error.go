@@ -0,0 +1,10 @@
+package metrics
+
+import "errors"
+
+// ErrMetricAlreadyRegistered is a sentinel error representing an attempt to register a metric under a name that is
+// already used by a different kind of metric.
+var ErrMetricAlreadyRegistered = errors.New("metric already registered with a different definition")
+
+// ErrRegistryCannotBeNil is a sentinel error representing an attempt to use a nil metrics registry.
+var ErrRegistryCannotBeNil = errors.New("metrics registry cannot be nil")
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// DefaultLatencyBuckets are the histogram bucket upper bounds, in seconds, suited to recording the latency of calls to
+// local or nearby services such as a cache.
+var DefaultLatencyBuckets = []float64{
+	0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1,
+}
+
+// Handler returns an HTTP handler that writes every metric gathered from the registry in the Prometheus exposition
+// format negotiated with the scraper. Metrics that fail to be gathered are skipped so that the rest are still served.
+func Handler(registry prometheus.Gatherer) http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{
+		ErrorHandling: promhttp.ContinueOnError,
+	})
+}
+
+// NewRegistry returns a new empty metrics registry.
+func NewRegistry() *prometheus.Registry {
+	return prometheus.NewRegistry()
+}
+
+// RegisterCounterVec returns the counter registered with the registry under the name of the options, registering a new
+// one with the label names if there is none, so that several components can share the same counter. Returns the
+// counter plus any error that may have occurred, including ErrMetricAlreadyRegistered if the name is used by a
+// different kind of metric.
+func RegisterCounterVec(
+	registry prometheus.Registerer, opts prometheus.CounterOpts, labelNames ...string,
+) (*prometheus.CounterVec, error) {
+	if registry == nil {
+		return nil, ErrRegistryCannotBeNil
+	}
+	return register(registry, prometheus.NewCounterVec(opts, labelNames))
+}
+
+// RegisterHistogramVec returns the histogram registered with the registry under the name of the options, registering a
+// new one with the label names if there is none, so that several components can share the same histogram. Returns the
+// histogram plus any error that may have occurred, including ErrMetricAlreadyRegistered if the name is used by a
+// different kind of metric.
+func RegisterHistogramVec(
+	registry prometheus.Registerer, opts prometheus.HistogramOpts, labelNames ...string,
+) (*prometheus.HistogramVec, error) {
+	if registry == nil {
+		return nil, ErrRegistryCannotBeNil
+	}
+	return register(registry, prometheus.NewHistogramVec(opts, labelNames))
+}
+
+// register registers the collector with the registry, returning the collector that is already registered instead if it
+// has the same definition. Returns the registered collector plus any error that may have occurred.
+func register[T prometheus.Collector](registry prometheus.Registerer, collector T) (T, error) {
+	var none T
+	err := registry.Register(collector)
+	if err == nil {
+		return collector, nil
+	}
+	alreadyRegistered := prometheus.AlreadyRegisteredError{}
+	if !errors.As(err, &alreadyRegistered) {
+		return none, err
+	}
+	existing, isSameKind := alreadyRegistered.ExistingCollector.(T)
+	if !isSameKind {
+		return none, fmt.Errorf("%w: %w", ErrMetricAlreadyRegistered, err)
+	}
+	return existing, nil
+}
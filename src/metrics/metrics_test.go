@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"errors"
+	gohttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestRegisterCounterVecReturnsTheRegisteredCounter(t *testing.T) {
+	registry := NewRegistry()
+	opts := prometheus.CounterOpts{Name: "requests_total", Help: "Number of requests."}
+	first, err := RegisterCounterVec(registry, opts, "route")
+	if err != nil {
+		t.Fatalf("RegisterCounterVec() error = %v", err)
+	}
+	second, err := RegisterCounterVec(registry, opts, "route")
+	if err != nil {
+		t.Fatalf("RegisterCounterVec() second error = %v", err)
+	}
+	if first != second {
+		t.Error("RegisterCounterVec() returned a new counter, want the registered one")
+	}
+}
+
+func TestRegisterRejectsDifferentDefinitions(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(registry *prometheus.Registry) error
+		wantErr  error
+	}{
+		{"nil registry", func(registry *prometheus.Registry) error {
+			_, err := RegisterCounterVec(nil, prometheus.CounterOpts{Name: "requests_total", Help: "Requests."})
+			return err
+		}, ErrRegistryCannotBeNil},
+		{"different kind", func(registry *prometheus.Registry) error {
+			_, err := RegisterHistogramVec(registry, prometheus.HistogramOpts{Name: "requests_total", Help: "Requests."},
+				"route")
+			return err
+		}, ErrMetricAlreadyRegistered},
+		{"different labels", func(registry *prometheus.Registry) error {
+			_, err := RegisterCounterVec(registry, prometheus.CounterOpts{Name: "requests_total", Help: "Requests."},
+				"method")
+			return err
+		}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registry := NewRegistry()
+			_, err := RegisterCounterVec(registry, prometheus.CounterOpts{Name: "requests_total", Help: "Requests."},
+				"route")
+			if err != nil {
+				t.Fatalf("RegisterCounterVec() error = %v", err)
+			}
+			err = test.register(registry)
+			if err == nil || (test.wantErr != nil && !errors.Is(err, test.wantErr)) {
+				t.Errorf("register error = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestHandlerWritesRegisteredMetrics(t *testing.T) {
+	registry := NewRegistry()
+	counter, err := RegisterCounterVec(registry, prometheus.CounterOpts{Name: "requests_total", Help: "Requests."},
+		"route")
+	if err != nil {
+		t.Fatalf("RegisterCounterVec() error = %v", err)
+	}
+	histogram, err := RegisterHistogramVec(registry, prometheus.HistogramOpts{
+		Name:    "request_duration_seconds",
+		Help:    "Request latency.",
+		Buckets: []float64{0.1, 1},
+	}, "route")
+	if err != nil {
+		t.Fatalf("RegisterHistogramVec() error = %v", err)
+	}
+	counter.WithLabelValues(`/a"b`).Inc()
+	histogram.WithLabelValues("/a").Observe(0.5)
+	recorder := httptest.NewRecorder()
+	Handler(registry).ServeHTTP(recorder, httptest.NewRequest(gohttp.MethodGet, "/metrics", nil))
+	body := recorder.Body.String()
+	for _, want := range []string{
+		`requests_total{route="/a\"b"} 1`,
+		`request_duration_seconds_bucket{route="/a",le="0.1"} 0`,
+		`request_duration_seconds_bucket{route="/a",le="1"} 1`,
+		`request_duration_seconds_bucket{route="/a",le="+Inf"} 1`,
+		`request_duration_seconds_sum{route="/a"} 0.5`,
+		`request_duration_seconds_count{route="/a"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Handler() body does not contain %q:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package ratelimit
+
+import "errors"
+
+// ErrInvalidAlgorithm is a sentinel error representing a rate limiting algorithm that is not supported.
+var ErrInvalidAlgorithm = errors.New("invalid rate limiting algorithm")
+
+// ErrInvalidRule is a sentinel error representing a rate limiting rule whose values are out of range.
+var ErrInvalidRule = errors.New("invalid rate limiting rule")
+
+// ErrLimiterCannotBeNil is a sentinel error representing an attempt to use a nil rate limiter.
+var ErrLimiterCannotBeNil = errors.New("rate limiter instance cannot be nil")
+
+// ErrRateLimited is a sentinel error representing a request that was rejected because its quota has been used up.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// ErrRequestExceedsBurst is a sentinel error representing a request for more units than the rule can ever allow at
+// once.
+var ErrRequestExceedsBurst = errors.New("request exceeds rate limit burst")
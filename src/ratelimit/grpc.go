@@ -0,0 +1,109 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCKeyFunc represents a function that chooses the rate limiting key of a gRPC call given its full method name (e.g.,
+// "/liveness.LivenessService/Live").
+type GRPCKeyFunc func(ctx context.Context, fullMethod string) string
+
+// GetPeerKey returns a rate limiting key made up of the host address of the calling peer and the full method name, so
+// that every client has its own quota for every method. Calls proxied by a gateway on the loopback interface (e.g.,
+// the gRPC-Gateway) are keyed by the last address within their "x-forwarded-for" metadata instead, which is the
+// address the gateway saw the request come from. Use NewPeerKeyFunc if there are other proxies in front of the gateway.
+func GetPeerKey(ctx context.Context, fullMethod string) string {
+	return getPeerKey(ctx, fullMethod, 0)
+}
+
+// NewPeerKeyFunc returns a key function that works like GetPeerKey for a gateway that sits behind the given number of
+// trusted proxies (e.g., one for a load balancer). Calls proxied by the gateway are keyed by the address that the
+// outermost trusted proxy saw the request come from; addresses further to the left within the "x-forwarded-for"
+// metadata are supplied by the client and ignored, so that clients cannot choose their own quota.
+func NewPeerKeyFunc(trustedProxies int) GRPCKeyFunc {
+	trustedProxies = max(trustedProxies, 0)
+	return func(ctx context.Context, fullMethod string) string {
+		return getPeerKey(ctx, fullMethod, trustedProxies)
+	}
+}
+
+// StreamServerInterceptor returns a gRPC stream interceptor that rejects streams once their quota has been used up.
+// See UnaryServerInterceptor for how calls are keyed and rejected.
+func StreamServerInterceptor(limiter *Limiter, keyFunc GRPCKeyFunc) grpc.StreamServerInterceptor {
+	if keyFunc == nil {
+		keyFunc = GetPeerKey
+	}
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := stream.Context()
+		if err := checkGRPCQuota(ctx, limiter, keyFunc(ctx, info.FullMethod)); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
+
+// UnaryServerInterceptor returns a gRPC unary interceptor that rejects calls once their quota has been used up. Calls
+// are keyed with the key function (GetPeerKey if it is nil). Rejected calls fail with codes.ResourceExhausted and carry
+// "retry-after" (in whole seconds) and "x-ratelimit-remaining" response headers. If the limiter itself fails, the call
+// is allowed through.
+func UnaryServerInterceptor(limiter *Limiter, keyFunc GRPCKeyFunc) grpc.UnaryServerInterceptor {
+	if keyFunc == nil {
+		keyFunc = GetPeerKey
+	}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := checkGRPCQuota(ctx, limiter, keyFunc(ctx, info.FullMethod)); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// checkGRPCQuota takes a request for the key from the limiter. Returns a gRPC status error if the request was rejected.
+func checkGRPCQuota(ctx context.Context, limiter *Limiter, key string) error {
+	result, err := limiter.Allow(ctx, key)
+	if err != nil || result.Allowed {
+		return nil
+	}
+	retryAfter := int64(math.Ceil(result.RetryAfter.Seconds()))
+	_ = grpc.SetHeader(ctx, metadata.Pairs(
+		"retry-after", strconv.FormatInt(retryAfter, 10),
+		"x-ratelimit-remaining", strconv.Itoa(result.Remaining),
+	))
+	return status.Errorf(codes.ResourceExhausted, "%s; retry after %s", ErrRateLimited, result.RetryAfter)
+}
+
+// getPeerKey returns the rate limiting key of the call as described by NewPeerKeyFunc.
+func getPeerKey(ctx context.Context, fullMethod string, trustedProxies int) string {
+	address := "unknown"
+	if p, exists := peer.FromContext(ctx); exists && p.Addr != nil {
+		address = p.Addr.String()
+		if host, _, err := net.SplitHostPort(address); err == nil {
+			address = host
+		}
+	}
+	if ip := net.ParseIP(address); ip != nil && ip.IsLoopback() {
+		// proxies append the address they received the request from, so only the rightmost entries can be trusted
+		forwarded := []string{}
+		for _, value := range metadata.ValueFromIncomingContext(ctx, "x-forwarded-for") {
+			for _, entry := range strings.Split(value, ",") {
+				if entry = strings.TrimSpace(entry); entry != "" {
+					forwarded = append(forwarded, entry)
+				}
+			}
+		}
+		if len(forwarded) > 0 {
+			address = forwarded[max(len(forwarded)-1-trustedProxies, 0)]
+		}
+	}
+	return address + fullMethod
+}
@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// makePeerContext returns an incoming call context from the peer address carrying the "x-forwarded-for" values.
+func makePeerContext(address string, forwarded ...string) context.Context {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP(address), Port: 50000},
+	})
+	pairs := []string{}
+	for _, value := range forwarded {
+		pairs = append(pairs, "x-forwarded-for", value)
+	}
+	return metadata.NewIncomingContext(ctx, metadata.Pairs(pairs...))
+}
+
+func TestGetPeerKey(t *testing.T) {
+	tests := []struct {
+		name           string
+		address        string
+		forwarded      []string
+		trustedProxies int
+		want           string
+	}{
+		{"direct peer", "203.0.113.7", nil, 0, "203.0.113.7/svc/Method"},
+		{"direct peer ignores forwarded header", "203.0.113.7", []string{"198.51.100.1"}, 0, "203.0.113.7/svc/Method"},
+		{"gateway without forwarded header", "127.0.0.1", nil, 0, "127.0.0.1/svc/Method"},
+		{"gateway appended address", "127.0.0.1", []string{"203.0.113.7"}, 0, "203.0.113.7/svc/Method"},
+		{"spoofed entries are ignored", "127.0.0.1", []string{"1.2.3.4, 5.6.7.8, 203.0.113.7"}, 0,
+			"203.0.113.7/svc/Method"},
+		{"spoofed header values are ignored", "127.0.0.1", []string{"1.2.3.4", "203.0.113.7"}, 0,
+			"203.0.113.7/svc/Method"},
+		{"one trusted proxy", "127.0.0.1", []string{"1.2.3.4, 203.0.113.7, 10.0.0.2"}, 1, "203.0.113.7/svc/Method"},
+		{"more trusted proxies than entries", "127.0.0.1", []string{"203.0.113.7, 10.0.0.2"}, 5,
+			"203.0.113.7/svc/Method"},
+		{"negative trusted proxies", "127.0.0.1", []string{"1.2.3.4, 203.0.113.7"}, -1, "203.0.113.7/svc/Method"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := makePeerContext(test.address, test.forwarded...)
+			if got := NewPeerKeyFunc(test.trustedProxies)(ctx, "/svc/Method"); got != test.want {
+				t.Errorf("NewPeerKeyFunc(%d) = %q, want %q", test.trustedProxies, got, test.want)
+			}
+		})
+	}
+	if got := GetPeerKey(makePeerContext("127.0.0.1", "1.2.3.4, 203.0.113.7"), "/svc/Method"); got !=
+		"203.0.113.7/svc/Method" {
+		t.Errorf("GetPeerKey() = %q, want the address appended by the gateway", got)
+	}
+}
+
+func TestUnaryServerInterceptorLimitsSpoofedForwardedFor(t *testing.T) {
+	limiter, err := NewMemoryLimiter(Rule{
+		Algorithm: AlgorithmSlidingWindow,
+		Limit:     2,
+		Window:    time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("NewMemoryLimiter() error = %v", err)
+	}
+	interceptor := UnaryServerInterceptor(limiter, nil)
+	info := &grpc.UnaryServerInfo{FullMethod: "/svc/Method"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return "ok", nil
+	}
+	for i := range 5 {
+		// the client sends a different X-Forwarded-For every time and the gateway appends the real address
+		ctx := makePeerContext("127.0.0.1", "10.0.0."+strconv.Itoa(i)+", 203.0.113.7")
+		_, err := interceptor(ctx, nil, info, handler)
+		if i < 2 && err != nil {
+			t.Fatalf("call %d error = %v, want it to be allowed", i, err)
+		}
+		if i >= 2 && status.Code(err) != codes.ResourceExhausted {
+			t.Fatalf("call %d code = %v, want %v", i, status.Code(err), codes.ResourceExhausted)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// memoryState represents the rate limiting state of a single key held in memory.
+type memoryState struct {
+	// requests are the times of the requests allowed within the current window (sliding window only).
+	requests []time.Time
+
+	// tokens is the number of tokens in the bucket as of the updated time (token bucket only).
+	tokens float64
+
+	// updated is the time the state was last updated.
+	updated time.Time
+}
+
+// memoryStore keeps the rate limiting state in memory. It also contains a mutex so it should ONLY be passed around
+// by-reference and never by-value.
+type memoryStore struct {
+	lastSweep time.Time
+	mu        sync.Mutex
+	states    map[string]*memoryState
+}
+
+// take applies the rule to the key and, if the request for n units fits within the quota, takes them.
+func (s *memoryStore) take(ctx context.Context, key string, rule Rule, n int) (*Result, error) {
+	// ensure we don't get a collision if two or more goroutines try to write concurrently
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	s.sweep(now, rule.Window*time.Duration((rule.capacity()+rule.Limit-1)/rule.Limit))
+	state, exists := s.states[key]
+	if !exists {
+		state = &memoryState{
+			tokens:  float64(rule.capacity()),
+			updated: now,
+		}
+		s.states[key] = state
+	}
+	if rule.Algorithm == AlgorithmSlidingWindow {
+		return takeSlidingWindow(state, rule, n, now), nil
+	}
+	return takeTokenBucket(state, rule, n, now), nil
+}
+
+// sweep removes the state of every key that has been idle for long enough to have its full quota back, at most once
+// per that period, so that the store does not grow without bound. The caller must hold the lock.
+func (s *memoryStore) sweep(now time.Time, idle time.Duration) {
+	if now.Sub(s.lastSweep) < idle {
+		return
+	}
+	s.lastSweep = now
+	for key, state := range s.states {
+		if now.Sub(state.updated) >= idle {
+			delete(s.states, key)
+		}
+	}
+}
+
+// newMemoryStore returns a new empty in-memory store.
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		lastSweep: time.Now(),
+		states:    map[string]*memoryState{},
+	}
+}
+
+// takeSlidingWindow applies the sliding window algorithm to the state.
+func takeSlidingWindow(state *memoryState, rule Rule, n int, now time.Time) *Result {
+	// requests are kept in time order, so everything before the first one within the window has expired
+	start := 0
+	for start < len(state.requests) && now.Sub(state.requests[start]) >= rule.Window {
+		start++
+	}
+	state.requests = state.requests[start:]
+	result := &Result{
+		Limit: rule.Limit,
+	}
+	count := len(state.requests)
+	if count+n <= rule.Limit {
+		for range n {
+			state.requests = append(state.requests, now)
+		}
+		count += n
+		result.Allowed = true
+	} else {
+		blocking := state.requests[count+n-rule.Limit-1]
+		result.RetryAfter = blocking.Add(rule.Window).Sub(now)
+	}
+	state.updated = now
+	result.Remaining = rule.Limit - count
+	if len(state.requests) > 0 {
+		result.ResetAfter = state.requests[0].Add(rule.Window).Sub(now)
+	}
+	return result
+}
+
+// takeTokenBucket applies the token bucket algorithm to the state.
+func takeTokenBucket(state *memoryState, rule Rule, n int, now time.Time) *Result {
+	capacity := float64(rule.capacity())
+	interval := float64(rule.Window) / float64(rule.Limit)
+	state.tokens = math.Min(capacity, state.tokens+float64(now.Sub(state.updated))/interval)
+	state.updated = now
+	result := &Result{
+		Limit: rule.capacity(),
+	}
+	if state.tokens >= float64(n) {
+		state.tokens -= float64(n)
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration(math.Ceil((float64(n) - state.tokens) * interval))
+	}
+	result.Remaining = int(math.Floor(state.tokens))
+	result.ResetAfter = time.Duration(math.Ceil((capacity - state.tokens) * interval))
+	return result
+}
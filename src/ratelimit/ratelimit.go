@@ -0,0 +1,300 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sepulchrestudios/go-service/src/cache"
+)
+
+// Algorithm represents the strategy used to decide whether a request fits within its quota.
+type Algorithm string
+
+const (
+	// AlgorithmSlidingWindow allows at most Limit requests within any period of length Window.
+	AlgorithmSlidingWindow Algorithm = "sliding_window"
+
+	// AlgorithmTokenBucket refills Limit tokens per Window up to a capacity of Burst tokens, with every request taking
+	// one token. Short bursts are allowed while the average rate stays within the limit.
+	AlgorithmTokenBucket Algorithm = "token_bucket"
+)
+
+// DefaultKeyPrefix is the prefix added to rate limiting keys to build their cache keys when no other prefix has been
+// provided.
+const DefaultKeyPrefix = "ratelimit:"
+
+// Rule is a struct representing the quota enforced by a rate limiter.
+type Rule struct {
+	// Algorithm is the strategy used to enforce the quota. Defaults to AlgorithmTokenBucket.
+	Algorithm Algorithm
+
+	// Burst is the capacity of the token bucket. It is ignored by the sliding window algorithm. A zero value uses the
+	// Limit.
+	Burst int
+
+	// Limit is the number of requests allowed per Window. A zero value turns rate limiting off.
+	Limit int
+
+	// Window is the period over which the Limit applies.
+	Window time.Duration
+}
+
+// capacity returns the largest number of units the rule can allow at once.
+func (r Rule) capacity() int {
+	if r.Algorithm == AlgorithmTokenBucket && r.Burst > 0 {
+		return r.Burst
+	}
+	return r.Limit
+}
+
+// Result is a struct representing the outcome of a rate limiting decision.
+type Result struct {
+	// Allowed describes whether the request fits within its quota.
+	Allowed bool
+
+	// Limit is the maximum number of requests that can be allowed at once.
+	Limit int
+
+	// Remaining is the number of further requests that would be allowed right now.
+	Remaining int
+
+	// ResetAfter is how long it takes for the quota to be fully available again.
+	ResetAfter time.Duration
+
+	// RetryAfter is how long to wait before the rejected request would be allowed. It is zero when the request was
+	// allowed.
+	RetryAfter time.Duration
+}
+
+// store represents the storage that keeps the rate limiting state of every key.
+type store interface {
+	// take applies the rule to the key and, if the request for n units fits within the quota, takes them.
+	take(ctx context.Context, key string, rule Rule, n int) (*Result, error)
+}
+
+// LimiterOptions is a struct representing the optional behaviour of a Limiter.
+type LimiterOptions struct {
+	// DisableFallback turns off the in-memory fallback that is used while Redis cannot be reached, in which case the
+	// errors are returned to the caller instead.
+	DisableFallback bool
+
+	// KeyPrefix is added to every key to build its cache key. Defaults to DefaultKeyPrefix.
+	KeyPrefix string
+
+	// OnError is called with every error returned by Redis before the in-memory fallback is used. It may be nil.
+	OnError func(err error)
+}
+
+// Limiter enforces a rate limiting rule for any number of keys (e.g., one per client address). The rule can be
+// replaced at any time with SetRule. It also contains a mutex so it should ONLY be passed around by-reference and never
+// by-value.
+//
+// When backed by Redis, the quota is shared by every instance using the same Redis cache and each decision is made
+// atomically by a script. If Redis cannot be reached, decisions fall back to an in-memory store that only knows about
+// the requests seen by this instance.
+type Limiter struct {
+	fallback  store
+	keyPrefix string
+	mu        sync.Mutex
+	onError   func(err error)
+	primary   store
+	rule      Rule
+}
+
+// Allow reports whether a single request for the key fits within the quota, taking it from the quota if it does.
+// Returns the result plus any error that may have occurred.
+func (l *Limiter) Allow(ctx context.Context, key string) (*Result, error) {
+	return l.AllowN(ctx, key, 1)
+}
+
+// AllowN reports whether a request for n units for the key fits within the quota, taking them from the quota if it
+// does. Returns the result plus any error that may have occurred.
+func (l *Limiter) AllowN(ctx context.Context, key string, n int) (*Result, error) {
+	if l == nil || l.primary == nil {
+		return nil, ErrLimiterCannotBeNil
+	}
+	rule := l.Rule()
+	if rule.Limit == 0 || n <= 0 {
+		return &Result{Allowed: true, Limit: rule.Limit, Remaining: rule.Limit}, nil
+	}
+	if n > rule.capacity() {
+		return nil, fmt.Errorf("%w: %d > %d", ErrRequestExceedsBurst, n, rule.capacity())
+	}
+	storeKey := l.keyPrefix + string(rule.Algorithm) + ":" + key
+	result, err := l.primary.take(ctx, storeKey, rule, n)
+	if err != nil && l.fallback != nil && ctx.Err() == nil {
+		if l.onError != nil {
+			l.onError(err)
+		}
+		return l.fallback.take(ctx, storeKey, rule, n)
+	}
+	return result, err
+}
+
+// Rule returns the rule currently enforced by the limiter.
+func (l *Limiter) Rule() Rule {
+	if l == nil {
+		return Rule{}
+	}
+	// ensure we don't get a collision if two or more goroutines try to read concurrently
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rule
+}
+
+// SetRule replaces the rule enforced by the limiter. Keys keep their state unless the algorithm changes, in which case
+// every key starts with a full quota. Returns any error that may have occurred.
+func (l *Limiter) SetRule(rule Rule) error {
+	if l == nil {
+		return ErrLimiterCannotBeNil
+	}
+	rule, err := normalizeRule(rule)
+	if err != nil {
+		return err
+	}
+	// ensure we don't get a collision if two or more goroutines try to write concurrently
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rule = rule
+	return nil
+}
+
+// Wait blocks until a single request for the key fits within the quota, taking it from the quota, or until the context
+// is done. Returns ErrRateLimited along with the context error if the context is done first.
+func (l *Limiter) Wait(ctx context.Context, key string) error {
+	for {
+		result, err := l.Allow(ctx, key)
+		if err != nil {
+			return err
+		}
+		if result.Allowed {
+			return nil
+		}
+		timer := time.NewTimer(max(result.RetryAfter, time.Millisecond))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w: %s: %w", ErrRateLimited, key, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// NewMemoryLimiter returns a new limiter that enforces the rule using in-memory state only, so the quota is not shared
+// with other instances. Returns the limiter plus any error that may have occurred.
+func NewMemoryLimiter(rule Rule) (*Limiter, error) {
+	rule, err := normalizeRule(rule)
+	if err != nil {
+		return nil, err
+	}
+	return &Limiter{
+		keyPrefix: DefaultKeyPrefix,
+		primary:   newMemoryStore(),
+		rule:      rule,
+	}, nil
+}
+
+// NewRedisLimiter returns a new limiter that enforces the rule using state stored within the provided Redis cache. The
+// options may be nil. Returns the limiter plus any error that may have occurred.
+func NewRedisLimiter(redisCache *cache.Redis, rule Rule, options *LimiterOptions) (*Limiter, error) {
+	if redisCache == nil || redisCache.Client() == nil {
+		return nil, cache.ErrCacheCannotBeNil
+	}
+	rule, err := normalizeRule(rule)
+	if err != nil {
+		return nil, err
+	}
+	limiter := &Limiter{
+		fallback:  newMemoryStore(),
+		keyPrefix: DefaultKeyPrefix,
+		primary:   newRedisStore(redisCache.Client()),
+		rule:      rule,
+	}
+	if options != nil {
+		if options.DisableFallback {
+			limiter.fallback = nil
+		}
+		if options.KeyPrefix != "" {
+			limiter.keyPrefix = options.KeyPrefix
+		}
+		limiter.onError = options.OnError
+	}
+	return limiter, nil
+}
+
+// NewLimiterFromCache returns a new limiter that uses Redis if the provided cache is (or wraps) a Redis cache, and
+// in-memory state otherwise. The options may be nil. Returns the limiter plus any error that may have occurred.
+//
+// If the provided cache is (or wraps) a namespaced cache, its prefix is added in front of the key prefix so that rate
+// limiting keys share the namespace of every other key.
+func NewLimiterFromCache(cacheImplementation cache.Contract, rule Rule, options *LimiterOptions) (*Limiter, error) {
+	redisCache, namespacePrefix := findRedis(cacheImplementation)
+	var limiter *Limiter
+	var err error
+	if redisCache != nil {
+		limiter, err = NewRedisLimiter(redisCache, rule, options)
+	} else {
+		limiter, err = NewMemoryLimiter(rule)
+		if err == nil && options != nil && options.KeyPrefix != "" {
+			limiter.keyPrefix = options.KeyPrefix
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	limiter.keyPrefix = namespacePrefix + limiter.keyPrefix
+	return limiter, nil
+}
+
+// ValidateRule takes a rule and returns an error if any of its values are invalid. Returns nil if the validation
+// checks pass.
+func ValidateRule(rule Rule) error {
+	switch rule.Algorithm {
+	case "", AlgorithmSlidingWindow, AlgorithmTokenBucket:
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidAlgorithm, rule.Algorithm)
+	}
+	if rule.Limit < 0 || rule.Burst < 0 {
+		return fmt.Errorf("%w: limit and burst cannot be negative", ErrInvalidRule)
+	}
+	if rule.Limit > 0 && rule.Window < time.Millisecond {
+		return fmt.Errorf("%w: window must be at least one millisecond", ErrInvalidRule)
+	}
+	return nil
+}
+
+// findRedis returns the Redis cache behind the provided cache, unwrapping known decorators, or nil if there is none.
+// Also returns the prefixes of any namespaced caches that were unwrapped along the way, combined the same way they
+// are applied to keys.
+func findRedis(cacheImplementation cache.Contract) (*cache.Redis, string) {
+	prefix := ""
+	for cacheImplementation != nil {
+		switch implementation := cacheImplementation.(type) {
+		case *cache.Redis:
+			return implementation, prefix
+		case *cache.Tiered:
+			return implementation.L2(), prefix
+		case *cache.Namespaced:
+			prefix = implementation.Prefix() + prefix
+			cacheImplementation = implementation.Implementation()
+		case interface{ Implementation() cache.Contract }:
+			cacheImplementation = implementation.Implementation()
+		default:
+			return nil, prefix
+		}
+	}
+	return nil, prefix
+}
+
+// normalizeRule validates the rule and fills in its defaults. Returns the rule plus any error that may have occurred.
+func normalizeRule(rule Rule) (Rule, error) {
+	if err := ValidateRule(rule); err != nil {
+		return rule, err
+	}
+	if rule.Algorithm == "" {
+		rule.Algorithm = AlgorithmTokenBucket
+	}
+	return rule, nil
+}
@@ -0,0 +1,124 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindowScript records each allowed request within a sorted set scored by the time it was made. Requests older
+// than the window are removed before the remaining ones are counted. Times are read from the Redis server in
+// microseconds so that every instance shares the same clock.
+//
+// KEYS[1] is the sorted set; ARGV holds the limit, the window in microseconds, the number of units requested and a
+// unique request ID. Returns whether the request was allowed, the remaining quota, the retry-after duration and the
+// reset-after duration, with the durations in microseconds.
+var slidingWindowScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local requested = tonumber(ARGV[3])
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - window)
+local count = redis.call("ZCARD", KEYS[1])
+local allowed = 0
+local retry_after = 0
+if count + requested <= limit then
+	for i = 1, requested do
+		redis.call("ZADD", KEYS[1], now, ARGV[4] .. ":" .. i)
+	end
+	count = count + requested
+	allowed = 1
+else
+	local blocking = redis.call("ZRANGE", KEYS[1], count + requested - limit - 1, count + requested - limit - 1,
+		"WITHSCORES")
+	retry_after = tonumber(blocking[2]) + window - now
+end
+local reset_after = 0
+local oldest = redis.call("ZRANGE", KEYS[1], 0, 0, "WITHSCORES")
+if oldest[2] then
+	reset_after = tonumber(oldest[2]) + window - now
+	redis.call("PEXPIRE", KEYS[1], math.ceil(window / 1000))
+end
+return {allowed, limit - count, retry_after, reset_after}
+`)
+
+// tokenBucketScript keeps the number of tokens in a bucket and the time it was last updated within a hash. Tokens are
+// refilled for the time that has passed before the request is taken. Times are read from the Redis server in
+// microseconds so that every instance shares the same clock.
+//
+// KEYS[1] is the hash; ARGV holds the limit, the window in microseconds, the burst and the number of units requested.
+// Returns whether the request was allowed, the remaining quota, the retry-after duration and the reset-after duration,
+// with the durations in microseconds.
+var tokenBucketScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local burst = tonumber(ARGV[3])
+local requested = tonumber(ARGV[4])
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+local interval = window / limit
+local state = redis.call("HMGET", KEYS[1], "tokens", "updated")
+local tokens = tonumber(state[1])
+local updated = tonumber(state[2])
+if tokens == nil or updated == nil then
+	tokens = burst
+	updated = now
+end
+tokens = math.min(burst, tokens + math.max(0, now - updated) / interval)
+local allowed = 0
+local retry_after = 0
+if tokens >= requested then
+	tokens = tokens - requested
+	allowed = 1
+else
+	retry_after = math.ceil((requested - tokens) * interval)
+end
+local reset_after = math.ceil((burst - tokens) * interval)
+redis.call("HSET", KEYS[1], "tokens", tokens, "updated", now)
+redis.call("PEXPIRE", KEYS[1], math.max(1, math.ceil(reset_after / 1000)))
+return {allowed, math.floor(tokens), retry_after, reset_after}
+`)
+
+// redisStore keeps the rate limiting state within Redis.
+type redisStore struct {
+	client redis.UniversalClient
+}
+
+// take applies the rule to the key and, if the request for n units fits within the quota, takes them.
+func (s *redisStore) take(ctx context.Context, key string, rule Rule, n int) (*Result, error) {
+	window := rule.Window.Microseconds()
+	var values []int64
+	var err error
+	switch rule.Algorithm {
+	case AlgorithmSlidingWindow:
+		values, err = slidingWindowScript.Run(ctx, s.client, []string{key}, rule.Limit, window, n, rand.Text()).
+			Int64Slice()
+	default:
+		values, err = tokenBucketScript.Run(ctx, s.client, []string{key}, rule.Limit, window, rule.capacity(), n).
+			Int64Slice()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 4 {
+		return nil, fmt.Errorf("unexpected rate limiting script result: %v", values)
+	}
+	return &Result{
+		Allowed:    values[0] == 1,
+		Limit:      rule.capacity(),
+		Remaining:  int(values[1]),
+		ResetAfter: time.Duration(values[3]) * time.Microsecond,
+		RetryAfter: time.Duration(values[2]) * time.Microsecond,
+	}, nil
+}
+
+// newRedisStore returns a new store that keeps the rate limiting state within Redis using the provided client.
+func newRedisStore(client redis.UniversalClient) *redisStore {
+	return &redisStore{
+		client: client,
+	}
+}
@@ -0,0 +1,39 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sepulchrestudios/go-service/src/work"
+)
+
+// WorkKeyFunc represents a function that chooses the rate limiting key of a work item.
+type WorkKeyFunc func(workItem work.WorkContract) string
+
+// GetWorkTypeKey returns the type of the work item as its rate limiting key, so that every work type has its own
+// quota.
+func GetWorkTypeKey(workItem work.WorkContract) string {
+	return string(workItem.Type())
+}
+
+// WrapHandler returns a work handler that only passes work items on to the provided handler while they fit within
+// their quota. Work items are keyed with the key function (GetWorkTypeKey if it is nil). Rejected work items produce a
+// failed result whose error wraps ErrRateLimited and whose return data is the rate limiting Result. If the limiter
+// itself fails, the work item is passed on.
+func WrapHandler(limiter *Limiter, keyFunc WorkKeyFunc, handler work.HandlerFunc) work.HandlerFunc {
+	if keyFunc == nil {
+		keyFunc = GetWorkTypeKey
+	}
+	return func(workItem work.WorkContract) work.WorkResultContract {
+		if workItem == nil || handler == nil {
+			return nil
+		}
+		key := keyFunc(workItem)
+		result, err := limiter.Allow(context.Background(), key)
+		if err == nil && !result.Allowed {
+			return work.NewResult(false, result, fmt.Errorf("%w: %s: retry after %s", ErrRateLimited, key,
+				result.RetryAfter), workItem)
+		}
+		return handler(workItem)
+	}
+}